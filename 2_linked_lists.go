@@ -1,6 +1,12 @@
 package main
 
-import "constraints"
+import (
+	"constraints"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+)
 
 type Node[T any] struct {
 	value T
@@ -41,6 +47,32 @@ func removeDuplicates[T constraints.Ordered](head *Node[T]) {
 	}
 }
 
+func withoutDuplicates[T constraints.Ordered](head *Node[T]) *Node[T] {
+	seenValues := make(map[T]bool)
+
+	var uniqueHead *Node[T]
+	var uniqueLast *Node[T]
+
+	for current := head; current != nil; current = current.next {
+		if seenValues[current.value] {
+			continue
+		}
+		seenValues[current.value] = true
+
+		node := &Node[T]{
+			value: current.value,
+		}
+		if uniqueHead == nil {
+			uniqueHead = node
+		} else {
+			uniqueLast.next = node
+		}
+		uniqueLast = node
+	}
+
+	return uniqueHead
+}
+
 func findLast[T constraints.Ordered](head *Node[T], indexFromEnd int) *Node[T] {
 
 	count := 0
@@ -102,12 +134,32 @@ func partition[T constraints.Ordered](head *Node[T], partitionValue T) *Node[T]
 	return biggerHead
 }
 
-type Numeric interface {
-	~int | ~int8 | ~int16 | ~int32 | ~int64 | ~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr | ~float32 | ~float64
+type Integer interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 | ~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr
+}
+
+type InvalidDigitError[T Integer] struct {
+	Digit    T
+	Radix    int
+	Position int
 }
 
-func sumReversed[T Numeric](numberA, numberB *Node[T]) *Node[T] {
+func (err *InvalidDigitError[T]) Error() string {
+	return fmt.Sprintf("Digit %v at position %d is not valid in radix %d", err.Digit, err.Position, err.Radix)
+}
+
+func sumReversed[T Integer](numberA, numberB *Node[T]) (*Node[T], error) {
+	return sumReversedInRadix(numberA, numberB, 10)
+}
 
+func sumReversedInRadix[T Integer](numberA, numberB *Node[T], radix int) (*Node[T], error) {
+	if err := validateNumbers(numberA, numberB, radix); err != nil {
+		return nil, err
+	}
+	return addReversedDigits(numberA, numberB, radix), nil
+}
+
+func addReversedDigits[T Integer](numberA, numberB *Node[T], radix int) *Node[T] {
 	var sumHead *Node[T]
 	var sumLast *Node[T]
 	carry := false
@@ -123,7 +175,7 @@ func sumReversed[T Numeric](numberA, numberB *Node[T]) *Node[T] {
 			valueB = numberB.value
 		}
 
-		sum, newCarry := calculateSum(valueA, valueB, carry)
+		sum, newCarry := calculateSum(valueA, valueB, carry, radix)
 		carry = newCarry
 
 		if sumHead == nil {
@@ -146,32 +198,78 @@ func sumReversed[T Numeric](numberA, numberB *Node[T]) *Node[T] {
 		}
 	}
 
+	if carry {
+		sumLast.next = &Node[T]{
+			value: 1,
+		}
+	}
+
 	return sumHead
 }
 
-func calculateSum[T Numeric](a, b T, previousCarry bool) (T, bool) {
-	sum := a + b
+func validateNumbers[T Integer](numberA, numberB *Node[T], radix int) error {
+	if err := validateDigits(numberA, radix); err != nil {
+		return err
+	}
+	return validateDigits(numberB, radix)
+}
+
+func validateDigits[T Integer](number *Node[T], radix int) error {
+	if radix < 2 {
+		return fmt.Errorf("Radix must be at least 2, got %d", radix)
+	}
+	if maximum := maxOf[T](); uint64(radix-1) > uint64(maximum) {
+		return fmt.Errorf("Radix %d does not fit digits of type %T with maximum %v", radix, maximum, maximum)
+	}
+
+	for position := 0; number != nil; position++ {
+		if number.value < 0 || uint64(number.value) >= uint64(radix) {
+			return &InvalidDigitError[T]{
+				Digit:    number.value,
+				Radix:    radix,
+				Position: position,
+			}
+		}
+		number = number.next
+	}
+	return nil
+}
+
+func maxOf[T Integer]() T {
+	maximum := T(1)
+	for maximum<<1+1 > maximum {
+		maximum = maximum<<1 + 1
+	}
+	return maximum
+}
+
+func calculateSum[T Integer](a, b T, previousCarry bool, radix int) (T, bool) {
+	sum := uint64(a) + uint64(b)
 	if previousCarry {
 		sum++
 	}
 
 	carry := false
 
-	if sum >= 10 {
+	if sum >= uint64(radix) {
 		carry = true
-		sum = sum - 10
+		sum = sum - uint64(radix)
 	}
 
-	return sum, carry
+	return T(sum), carry
 }
 
-func sum[T Numeric](numberA, numberB *Node[T]) *Node[T] {
+func sum[T Integer](numberA, numberB *Node[T]) (*Node[T], error) {
+	return sumInRadix(numberA, numberB, 10)
+}
 
-	reversedNumberA := reverseLinkedList(numberA)
-	reversedNumberB := reverseLinkedList(numberB)
-	reversedSum := sumReversed(reversedNumberA, reversedNumberB)
+func sumInRadix[T Integer](numberA, numberB *Node[T], radix int) (*Node[T], error) {
+	if err := validateNumbers(numberA, numberB, radix); err != nil {
+		return nil, err
+	}
 
-	return reverseLinkedList(reversedSum)
+	reversedSum := addReversedDigits(reversedLinkedList(numberA), reversedLinkedList(numberB), radix)
+	return reverseLinkedList(reversedSum), nil
 }
 
 func reverseLinkedList[T constraints.Ordered](head *Node[T]) *Node[T] {
@@ -189,32 +287,556 @@ func reverseLinkedList[T constraints.Ordered](head *Node[T]) *Node[T] {
 	return previous
 }
 
+func reversedLinkedList[T any](head *Node[T]) *Node[T] {
+	var reversed *Node[T]
+
+	for current := head; current != nil; current = current.next {
+		reversed = &Node[T]{
+			value: current.value,
+			next:  reversed,
+		}
+	}
+
+	return reversed
+}
+
+func copyLinkedList[T any](head *Node[T]) *Node[T] {
+	var copyHead *Node[T]
+	var copyLast *Node[T]
+
+	for current := head; current != nil; current = current.next {
+		node := &Node[T]{
+			value: current.value,
+		}
+		if copyHead == nil {
+			copyHead = node
+		} else {
+			copyLast.next = node
+		}
+		copyLast = node
+	}
+
+	return copyHead
+}
+
 func findLoop[T constraints.Ordered](head *Node[T]) *Node[T] {
+	return detectCycleFloyd(head).Entry
+}
 
-	visitedNodes := make(map[*Node[T]]bool)
+type CycleInfo[T any] struct {
+	Entry       *Node[T]
+	CycleLength int
+	TailLength  int
+}
 
-	current := head
-	for current != nil {
-		if _, hasNode := visitedNodes[current]; hasNode {
-			return current
+func (info CycleInfo[T]) HasCycle() bool {
+	return info.Entry != nil
+}
+
+func detectCycleFloyd[T any](head *Node[T]) CycleInfo[T] {
+	slow := head
+	fast := head
+
+	for fast != nil && fast.next != nil {
+		slow = slow.next
+		fast = fast.next.next
+
+		if slow == fast {
+			cycleLength := 1
+			for current := slow.next; current != slow; current = current.next {
+				cycleLength++
+			}
+
+			entry := head
+			tailLength := 0
+			for entry != slow {
+				entry = entry.next
+				slow = slow.next
+				tailLength++
+			}
+
+			return CycleInfo[T]{
+				Entry:       entry,
+				CycleLength: cycleLength,
+				TailLength:  tailLength,
+			}
 		}
+	}
 
-		visitedNodes[current] = true
-		current = current.next
+	return CycleInfo[T]{
+		TailLength: listLength(head),
 	}
-	return nil
+}
+
+func detectCycleBrent[T any](head *Node[T]) CycleInfo[T] {
+	if head == nil {
+		return CycleInfo[T]{}
+	}
+
+	power := 1
+	cycleLength := 1
+	tortoise := head
+	hare := head.next
+
+	for tortoise != hare {
+		if hare == nil {
+			return CycleInfo[T]{
+				TailLength: listLength(head),
+			}
+		}
+
+		if power == cycleLength {
+			tortoise = hare
+			power *= 2
+			cycleLength = 0
+		}
+		hare = hare.next
+		cycleLength++
+	}
+
+	tortoise = head
+	hare = head
+	for i := 0; i < cycleLength; i++ {
+		hare = hare.next
+	}
+
+	tailLength := 0
+	for tortoise != hare {
+		tortoise = tortoise.next
+		hare = hare.next
+		tailLength++
+	}
+
+	return CycleInfo[T]{
+		Entry:       tortoise,
+		CycleLength: cycleLength,
+		TailLength:  tailLength,
+	}
+}
+
+func BreakCycle[T any](head *Node[T]) bool {
+	info := detectCycleBrent(head)
+	if !info.HasCycle() {
+		return false
+	}
+
+	last := advance(info.Entry, info.CycleLength-1)
+	last.next = nil
+
+	return true
+}
+
+// Intersection returns the first node of a that is also part of b. When both
+// lists end in the same cycle but enter it at different nodes, that is the
+// node where a enters the cycle, so the result depends on argument order.
+func Intersection[T any](a, b *Node[T]) *Node[T] {
+	cycleA := detectCycleBrent(a)
+	cycleB := detectCycleBrent(b)
+
+	if cycleA.HasCycle() != cycleB.HasCycle() {
+		return nil
+	}
+
+	if cycleA.HasCycle() && cycleA.Entry != cycleB.Entry {
+		for current := cycleA.Entry.next; current != cycleA.Entry; current = current.next {
+			if current == cycleB.Entry {
+				return cycleA.Entry
+			}
+		}
+		return nil
+	}
+
+	a = advance(a, cycleA.TailLength-cycleB.TailLength)
+	b = advance(b, cycleB.TailLength-cycleA.TailLength)
+
+	for a != b {
+		a = a.next
+		b = b.next
+	}
+	return a
+}
+
+func advance[T any](head *Node[T], steps int) *Node[T] {
+	for i := 0; i < steps; i++ {
+		head = head.next
+	}
+	return head
+}
+
+func listLength[T any](head *Node[T]) int {
+	length := 0
+	for current := head; current != nil; current = current.next {
+		length++
+	}
+	return length
 }
 
 func isPalindrom[T constraints.Ordered](head *Node[T]) bool {
-	reversedList := reverseLinkedList(head)
-	current := head
-	for current != nil {
-		if current.value != reversedList.value {
+	if head == nil || head.next == nil {
+		return true
+	}
+
+	slow := head
+	fast := head
+	for fast.next != nil && fast.next.next != nil {
+		slow = slow.next
+		fast = fast.next.next
+	}
+
+	secondHalf := reverseLinkedList(slow.next)
+	defer func() {
+		slow.next = reverseLinkedList(secondHalf)
+	}()
+
+	firstHalf := head
+	for current := secondHalf; current != nil; current = current.next {
+		if current.value != firstHalf.value {
 			return false
 		}
-
-		current = current.next
-		reversedList = reversedList.next
+		firstHalf = firstHalf.next
 	}
 	return true
 }
+
+const bigNumberDigits = "0123456789abcdefghijklmnopqrstuvwxyz"
+
+type BigNumber struct {
+	base     int
+	negative bool
+	digits   *Node[int]
+}
+
+func newBigNumber(value int64, base int) (*BigNumber, error) {
+	return newBigNumberFromBigInt(big.NewInt(value), base)
+}
+
+func newBigNumberFromBigInt(value *big.Int, base int) (*BigNumber, error) {
+	if err := validateBigNumberBase(base); err != nil {
+		return nil, err
+	}
+
+	number := &BigNumber{
+		base:     base,
+		negative: value.Sign() < 0,
+	}
+
+	remaining := new(big.Int).Abs(value)
+	bigBase := big.NewInt(int64(base))
+	digit := new(big.Int)
+	var last *Node[int]
+
+	for {
+		remaining.QuoRem(remaining, bigBase, digit)
+		node := &Node[int]{
+			value: int(digit.Int64()),
+		}
+		if last == nil {
+			number.digits = node
+		} else {
+			last.next = node
+		}
+		last = node
+
+		if remaining.Sign() == 0 {
+			break
+		}
+	}
+
+	return number, nil
+}
+
+func newBigNumberFromString(input string, base int) (*BigNumber, error) {
+	if err := validateBigNumberBase(base); err != nil {
+		return nil, err
+	}
+
+	negative := false
+	if strings.HasPrefix(input, "-") || strings.HasPrefix(input, "+") {
+		negative = input[0] == '-'
+		input = input[1:]
+	}
+	if len(input) == 0 {
+		return nil, errors.New("Number has no digits")
+	}
+
+	var digits *Node[int]
+	for _, char := range strings.ToLower(input) {
+		digit := strings.IndexRune(bigNumberDigits, char)
+		if digit < 0 || digit >= base {
+			return nil, fmt.Errorf("Invalid digit %q for base %d", char, base)
+		}
+		digits = &Node[int]{
+			value: digit,
+			next:  digits,
+		}
+	}
+
+	return newBigNumberFromDigits(digits, negative, base), nil
+}
+
+func newBigNumberFromDigits(digits *Node[int], negative bool, base int) *BigNumber {
+	digits = trimLeadingZeros(digits)
+	if digits == nil {
+		digits = &Node[int]{}
+	}
+
+	return &BigNumber{
+		base:     base,
+		negative: negative && !isZeroDigits(digits),
+		digits:   digits,
+	}
+}
+
+func validateBigNumberBase(base int) error {
+	if base < 2 || base > len(bigNumberDigits) {
+		return fmt.Errorf("Base must be between 2 and %d, got %d", len(bigNumberDigits), base)
+	}
+	return nil
+}
+
+func (number *BigNumber) Base() int {
+	return number.base
+}
+
+func (number *BigNumber) Sign() int {
+	if number.negative {
+		return -1
+	}
+	if isZeroDigits(number.digits) {
+		return 0
+	}
+	return 1
+}
+
+func (number *BigNumber) ToBase(base int) (*BigNumber, error) {
+	if base == number.base {
+		return number, nil
+	}
+	return newBigNumberFromBigInt(number.BigInt(), base)
+}
+
+func (number *BigNumber) BigInt() *big.Int {
+	digits := []int{}
+	for current := number.digits; current != nil; current = current.next {
+		digits = append(digits, current.value)
+	}
+
+	value := new(big.Int)
+	bigBase := big.NewInt(int64(number.base))
+	for index := len(digits) - 1; index >= 0; index-- {
+		value.Mul(value, bigBase)
+		value.Add(value, big.NewInt(int64(digits[index])))
+	}
+
+	if number.negative {
+		value.Neg(value)
+	}
+	return value
+}
+
+func (number *BigNumber) String() string {
+	chars := []byte{}
+	for current := number.digits; current != nil; current = current.next {
+		chars = append(chars, bigNumberDigits[current.value])
+	}
+	if number.negative {
+		chars = append(chars, '-')
+	}
+
+	return reverse(string(chars))
+}
+
+func (number *BigNumber) Negate() *BigNumber {
+	return newBigNumberFromDigits(number.digits, !number.negative, number.base)
+}
+
+func (number *BigNumber) Compare(other *BigNumber) int {
+	other = number.sameBase(other)
+
+	if number.negative != other.negative {
+		if number.negative {
+			return -1
+		}
+		return 1
+	}
+
+	comparison := compareDigits(number.digits, other.digits)
+	if number.negative {
+		return -comparison
+	}
+	return comparison
+}
+
+func (number *BigNumber) Add(other *BigNumber) *BigNumber {
+	other = number.sameBase(other)
+
+	if number.negative == other.negative {
+		digits := addDigits(number.digits, other.digits, number.base)
+		return newBigNumberFromDigits(digits, number.negative, number.base)
+	}
+
+	if compareDigits(number.digits, other.digits) >= 0 {
+		digits := subtractDigits(number.digits, other.digits, number.base)
+		return newBigNumberFromDigits(digits, number.negative, number.base)
+	}
+
+	digits := subtractDigits(other.digits, number.digits, number.base)
+	return newBigNumberFromDigits(digits, other.negative, number.base)
+}
+
+func (number *BigNumber) Subtract(other *BigNumber) *BigNumber {
+	return number.Add(number.sameBase(other).Negate())
+}
+
+func (number *BigNumber) Multiply(other *BigNumber) *BigNumber {
+	other = number.sameBase(other)
+
+	var product *Node[int]
+	position := 0
+
+	for current := other.digits; current != nil; current = current.next {
+		partial := multiplyDigitsByDigit(number.digits, current.value, number.base)
+		for shift := 0; shift < position; shift++ {
+			partial = &Node[int]{
+				next: partial,
+			}
+		}
+
+		product = addDigits(product, partial, number.base)
+		position++
+	}
+
+	return newBigNumberFromDigits(product, number.negative != other.negative, number.base)
+}
+
+func (number *BigNumber) sameBase(other *BigNumber) *BigNumber {
+	converted, _ := other.ToBase(number.base)
+	return converted
+}
+
+func addDigits(digitsA, digitsB *Node[int], base int) *Node[int] {
+	return addReversedDigits(digitsA, digitsB, base)
+}
+
+func subtractDigits(minuend, subtrahend *Node[int], base int) *Node[int] {
+	var head *Node[int]
+	var last *Node[int]
+	borrow := 0
+
+	for minuend != nil {
+		value := minuend.value - borrow
+		if subtrahend != nil {
+			value -= subtrahend.value
+			subtrahend = subtrahend.next
+		}
+
+		borrow = 0
+		if value < 0 {
+			value += base
+			borrow = 1
+		}
+
+		node := &Node[int]{
+			value: value,
+		}
+		if head == nil {
+			head = node
+		} else {
+			last.next = node
+		}
+		last = node
+
+		minuend = minuend.next
+	}
+
+	return trimLeadingZeros(head)
+}
+
+func multiplyDigitsByDigit(digits *Node[int], multiplier, base int) *Node[int] {
+	var head *Node[int]
+	var last *Node[int]
+	carry := 0
+
+	for digits != nil || carry != 0 {
+		value := carry
+		if digits != nil {
+			value += digits.value * multiplier
+			digits = digits.next
+		}
+
+		node := &Node[int]{
+			value: value % base,
+		}
+		carry = value / base
+
+		if head == nil {
+			head = node
+		} else {
+			last.next = node
+		}
+		last = node
+	}
+
+	return head
+}
+
+func compareDigits(digitsA, digitsB *Node[int]) int {
+	digitsA = trimLeadingZeros(digitsA)
+	digitsB = trimLeadingZeros(digitsB)
+	comparison := 0
+
+	for digitsA != nil && digitsB != nil {
+		if digitsA.value > digitsB.value {
+			comparison = 1
+		} else if digitsA.value < digitsB.value {
+			comparison = -1
+		}
+
+		digitsA = digitsA.next
+		digitsB = digitsB.next
+	}
+
+	if digitsA != nil {
+		return 1
+	}
+	if digitsB != nil {
+		return -1
+	}
+	return comparison
+}
+
+func trimLeadingZeros(digits *Node[int]) *Node[int] {
+	var lastNonZero *Node[int]
+	for current := digits; current != nil; current = current.next {
+		if current.value != 0 {
+			lastNonZero = current
+		}
+	}
+
+	if lastNonZero == nil {
+		return nil
+	}
+	if lastNonZero.next == nil {
+		return digits
+	}
+
+	var head *Node[int]
+	var last *Node[int]
+	for current := digits; current != lastNonZero.next; current = current.next {
+		node := &Node[int]{
+			value: current.value,
+		}
+		if head == nil {
+			head = node
+		} else {
+			last.next = node
+		}
+		last = node
+	}
+
+	return head
+}
+
+func isZeroDigits(digits *Node[int]) bool {
+	return trimLeadingZeros(digits) == nil
+}
@@ -1,7 +1,9 @@
 package main
 
 import (
+	"errors"
 	"github.com/stretchr/testify/assert"
+	"math/big"
 	"testing"
 )
 
@@ -116,6 +118,45 @@ func TestRemoveDuplicates(t *testing.T) {
 	})
 }
 
+func TestWithoutDuplicates(t *testing.T) {
+	t.Run("Returns list without duplicates and keeps original list", func(t *testing.T) {
+		list := Node[int]{
+			value: 20,
+			next: &Node[int]{
+				value: 21,
+				next: &Node[int]{
+					value: 20,
+					next: &Node[int]{
+						value: 23,
+						next:  nil,
+					},
+				},
+			},
+		}
+		originalList := copyLinkedList(&list)
+
+		uniqueList := withoutDuplicates(&list)
+
+		expectedList := &Node[int]{
+			value: 20,
+			next: &Node[int]{
+				value: 21,
+				next: &Node[int]{
+					value: 23,
+					next:  nil,
+				},
+			},
+		}
+
+		assert.Equal(t, expectedList, uniqueList)
+		assert.Equal(t, originalList, &list)
+	})
+
+	t.Run("Empty list", func(t *testing.T) {
+		assert.Nil(t, withoutDuplicates[int](nil))
+	})
+}
+
 func TestFindLast(t *testing.T) {
 	t.Run("Should return first node given one node list", func(t *testing.T) {
 		list := Node[int]{
@@ -298,7 +339,8 @@ func TestSumReversed(t *testing.T) {
 			},
 		}
 
-		actualSum := sumReversed(&numberA, &numberB)
+		actualSum, err := sumReversed(&numberA, &numberB)
+		assert.Nil(t, err)
 
 		expectedSum := &Node[int]{
 			value: 2,
@@ -334,7 +376,8 @@ func TestSumReversed(t *testing.T) {
 			},
 		}
 
-		actualSum := sumReversed(&numberA, &numberB)
+		actualSum, err := sumReversed(&numberA, &numberB)
+		assert.Nil(t, err)
 
 		expectedSum := &Node[int]{
 			value: 5,
@@ -349,9 +392,231 @@ func TestSumReversed(t *testing.T) {
 
 		assert.Equal(t, expectedSum, actualSum)
 	})
+
+	t.Run("999+1=1000", func(t *testing.T) {
+		numberA := Node[int]{
+			value: 9,
+			next: &Node[int]{
+				value: 9,
+				next: &Node[int]{
+					value: 9,
+					next:  nil,
+				},
+			},
+		}
+
+		numberB := Node[int]{
+			value: 1,
+			next:  nil,
+		}
+
+		actualSum, err := sumReversed(&numberA, &numberB)
+		assert.Nil(t, err)
+
+		expectedSum := &Node[int]{
+			value: 0,
+			next: &Node[int]{
+				value: 0,
+				next: &Node[int]{
+					value: 0,
+					next: &Node[int]{
+						value: 1,
+						next:  nil,
+					},
+				},
+			},
+		}
+
+		assert.Equal(t, expectedSum, actualSum)
+	})
+}
+
+func TestSumReversedReturnsInvalidDigitError(t *testing.T) {
+	numberA := Node[int]{
+		value: 1,
+		next:  nil,
+	}
+	numberB := Node[int]{
+		value: 10,
+		next:  nil,
+	}
+
+	actualSum, err := sumReversed(&numberA, &numberB)
+
+	var digitErr *InvalidDigitError[int]
+	assert.Nil(t, actualSum)
+	assert.True(t, errors.As(err, &digitErr))
+	assert.Equal(t, 10, digitErr.Digit)
+}
+
+func TestSumReversedInRadix(t *testing.T) {
+	t.Run("255+1=256 in base 256", func(t *testing.T) {
+		numberA := Node[byte]{
+			value: 255,
+			next:  nil,
+		}
+
+		numberB := Node[byte]{
+			value: 1,
+			next:  nil,
+		}
+
+		actualSum, err := sumReversedInRadix(&numberA, &numberB, 256)
+
+		expectedSum := &Node[byte]{
+			value: 0,
+			next: &Node[byte]{
+				value: 1,
+				next:  nil,
+			},
+		}
+
+		assert.Nil(t, err)
+		assert.Equal(t, expectedSum, actualSum)
+	})
+
+	t.Run("0x1F+0x01=0x20 in base 16", func(t *testing.T) {
+		numberA := Node[uint8]{
+			value: 0xF,
+			next: &Node[uint8]{
+				value: 0x1,
+				next:  nil,
+			},
+		}
+
+		numberB := Node[uint8]{
+			value: 0x1,
+			next:  nil,
+		}
+
+		actualSum, err := sumReversedInRadix(&numberA, &numberB, 16)
+
+		expectedSum := &Node[uint8]{
+			value: 0x0,
+			next: &Node[uint8]{
+				value: 0x2,
+				next:  nil,
+			},
+		}
+
+		assert.Nil(t, err)
+		assert.Equal(t, expectedSum, actualSum)
+	})
+
+	t.Run("Error given digit outside of radix", func(t *testing.T) {
+		numberA := Node[int]{
+			value: 1,
+			next: &Node[int]{
+				value: 16,
+				next:  nil,
+			},
+		}
+
+		numberB := Node[int]{
+			value: 1,
+			next:  nil,
+		}
+
+		actualSum, err := sumReversedInRadix(&numberA, &numberB, 16)
+
+		var digitErr *InvalidDigitError[int]
+		assert.Nil(t, actualSum)
+		assert.True(t, errors.As(err, &digitErr))
+		assert.Equal(t, 16, digitErr.Digit)
+		assert.Equal(t, 1, digitErr.Position)
+	})
+
+	t.Run("Error given negative digit", func(t *testing.T) {
+		numberA := Node[int]{
+			value: -1,
+			next:  nil,
+		}
+
+		_, err := sumReversedInRadix(&numberA, nil, 10)
+
+		var digitErr *InvalidDigitError[int]
+		assert.True(t, errors.As(err, &digitErr))
+	})
+
+	t.Run("Error given radix too large for digit type", func(t *testing.T) {
+		numberA := Node[uint8]{
+			value: 255,
+		}
+		numberB := Node[uint8]{
+			value: 44,
+		}
+
+		actualSum, err := sumReversedInRadix(&numberA, &numberB, 300)
+		assert.Nil(t, actualSum)
+		assert.NotNil(t, err)
+
+		signedA := Node[int8]{
+			value: 100,
+		}
+		signedB := Node[int8]{
+			value: 100,
+		}
+
+		signedSum, err := sumReversedInRadix(&signedA, &signedB, 129)
+		assert.Nil(t, signedSum)
+		assert.NotNil(t, err)
+	})
+
+	t.Run("Radix equal to digit type range is accepted", func(t *testing.T) {
+		numberA := Node[uint8]{
+			value: 255,
+		}
+		numberB := Node[uint8]{
+			value: 1,
+		}
+
+		actualSum, err := sumReversedInRadix(&numberA, &numberB, 256)
+		assert.Nil(t, err)
+		assert.Equal(t, &Node[uint8]{value: 0, next: &Node[uint8]{value: 1}}, actualSum)
+
+		signedA := Node[int8]{
+			value: 127,
+		}
+		signedSum, err := sumReversedInRadix(&signedA, &signedA, 128)
+		assert.Nil(t, err)
+		assert.Equal(t, &Node[int8]{value: 126, next: &Node[int8]{value: 1}}, signedSum)
+	})
+
+	t.Run("Error given radix lower than 2", func(t *testing.T) {
+		numberA := Node[int]{
+			value: 0,
+			next:  nil,
+		}
+
+		_, err := sumReversedInRadix(&numberA, &numberA, 1)
+
+		assert.NotNil(t, err)
+	})
 }
 
 func TestSum(t *testing.T) {
+	t.Run("Error given invalid digit", func(t *testing.T) {
+		numberA := Node[int]{
+			value: 6,
+			next: &Node[int]{
+				value: 12,
+				next: &Node[int]{
+					value: 7,
+					next:  nil,
+				},
+			},
+		}
+
+		actualSum, err := sum(&numberA, nil)
+
+		var digitErr *InvalidDigitError[int]
+		assert.Nil(t, actualSum)
+		assert.True(t, errors.As(err, &digitErr))
+		assert.Equal(t, 12, digitErr.Digit)
+		assert.Equal(t, 10, digitErr.Radix)
+		assert.Equal(t, 1, digitErr.Position)
+	})
+
 	t.Run("617+295=912", func(t *testing.T) {
 		numberA := Node[int]{
 			value: 6,
@@ -375,7 +640,8 @@ func TestSum(t *testing.T) {
 			},
 		}
 
-		actualSum := sum(&numberA, &numberB)
+		actualSum, err := sum(&numberA, &numberB)
+		assert.Nil(t, err)
 
 		expectedSum := &Node[int]{
 			value: 9,
@@ -411,7 +677,8 @@ func TestSum(t *testing.T) {
 			},
 		}
 
-		actualSum := sum(&numberA, &numberB)
+		actualSum, err := sum(&numberA, &numberB)
+		assert.Nil(t, err)
 
 		expectedSum := &Node[int]{
 			value: 6,
@@ -428,6 +695,34 @@ func TestSum(t *testing.T) {
 	})
 }
 
+func TestSumKeepsInputLists(t *testing.T) {
+	numberA := Node[int]{
+		value: 6,
+		next: &Node[int]{
+			value: 1,
+			next: &Node[int]{
+				value: 7,
+				next:  nil,
+			},
+		},
+	}
+
+	numberB := Node[int]{
+		value: 2,
+		next: &Node[int]{
+			value: 8,
+			next:  nil,
+		},
+	}
+	originalA := copyLinkedList(&numberA)
+	originalB := copyLinkedList(&numberB)
+
+	_, _ = sum(&numberA, &numberB)
+
+	assert.Equal(t, originalA, &numberA)
+	assert.Equal(t, originalB, &numberB)
+}
+
 func TestReverseLinkedList(t *testing.T) {
 
 	list := Node[int]{
@@ -469,6 +764,36 @@ func TestReverseLinkedList(t *testing.T) {
 	assert.Equal(t, &expectedList, reversedList)
 }
 
+func TestReversedLinkedList(t *testing.T) {
+	list := Node[int]{
+		value: 5,
+		next: &Node[int]{
+			value: 2,
+			next: &Node[int]{
+				value: 8,
+				next:  nil,
+			},
+		},
+	}
+	originalList := copyLinkedList(&list)
+
+	reversedList := reversedLinkedList(&list)
+
+	expectedList := &Node[int]{
+		value: 8,
+		next: &Node[int]{
+			value: 2,
+			next: &Node[int]{
+				value: 5,
+				next:  nil,
+			},
+		},
+	}
+
+	assert.Equal(t, expectedList, reversedList)
+	assert.Equal(t, originalList, &list)
+}
+
 func TestFindLoop(t *testing.T) {
 
 	t.Run("Without loop", func(t *testing.T) {
@@ -521,6 +846,215 @@ func TestFindLoop(t *testing.T) {
 	})
 }
 
+func TestDetectCycle(t *testing.T) {
+	detectors := map[string]func(*Node[int]) CycleInfo[int]{
+		"Floyd": detectCycleFloyd[int],
+		"Brent": detectCycleBrent[int],
+	}
+
+	for name, detect := range detectors {
+		detect := detect
+
+		t.Run(name+" without loop", func(t *testing.T) {
+			list := newLinkedListWithCycle(4, 0)
+
+			info := detect(list)
+
+			assert.False(t, info.HasCycle())
+			assert.Equal(t, 0, info.CycleLength)
+			assert.Equal(t, 4, info.TailLength)
+		})
+
+		t.Run(name+" given empty list", func(t *testing.T) {
+			info := detect(nil)
+
+			assert.False(t, info.HasCycle())
+			assert.Equal(t, 0, info.TailLength)
+		})
+
+		t.Run(name+" given node pointing to itself", func(t *testing.T) {
+			list := newLinkedListWithCycle(0, 1)
+
+			info := detect(list)
+
+			assert.Equal(t, list, info.Entry)
+			assert.Equal(t, 1, info.CycleLength)
+			assert.Equal(t, 0, info.TailLength)
+		})
+
+		t.Run(name+" finds entry, cycle and tail length", func(t *testing.T) {
+			for tailLength := 0; tailLength < 7; tailLength++ {
+				for cycleLength := 1; cycleLength < 7; cycleLength++ {
+					list := newLinkedListWithCycle(tailLength, cycleLength)
+					expectedEntry := list
+					for i := 0; i < tailLength; i++ {
+						expectedEntry = expectedEntry.next
+					}
+
+					info := detect(list)
+
+					assert.Equal(t, expectedEntry, info.Entry)
+					assert.Equal(t, cycleLength, info.CycleLength)
+					assert.Equal(t, tailLength, info.TailLength)
+				}
+			}
+		})
+	}
+}
+
+func TestBreakCycle(t *testing.T) {
+	t.Run("Returns false given list without loop", func(t *testing.T) {
+		list := newLinkedListWithCycle(3, 0)
+
+		assert.False(t, BreakCycle(list))
+		assert.Equal(t, 3, listLength(list))
+	})
+
+	t.Run("Turns cycle into list keeping all nodes", func(t *testing.T) {
+		list := newLinkedListWithCycle(3, 4)
+
+		assert.True(t, BreakCycle(list))
+		assert.False(t, detectCycleFloyd(list).HasCycle())
+		assert.Equal(t, 7, listLength(list))
+	})
+}
+
+func TestIntersection(t *testing.T) {
+	t.Run("Returns nil given separate lists", func(t *testing.T) {
+		listA := newLinkedListWithCycle(3, 0)
+		listB := newLinkedListWithCycle(3, 0)
+
+		assert.Nil(t, Intersection(listA, listB))
+	})
+
+	t.Run("Returns nil given empty list", func(t *testing.T) {
+		listA := newLinkedListWithCycle(3, 0)
+
+		assert.Nil(t, Intersection(listA, nil))
+		assert.Nil(t, Intersection[int](nil, nil))
+	})
+
+	t.Run("Returns cycle entry of first list given lists entering cycle at different nodes", func(t *testing.T) {
+		node2 := &Node[int]{value: 2}
+		node3 := &Node[int]{value: 3}
+		node4 := &Node[int]{value: 4}
+		node2.next = node3
+		node3.next = node4
+		node4.next = node2
+
+		listA := &Node[int]{
+			value: 1,
+			next:  node2,
+		}
+		listB := &Node[int]{
+			value: 5,
+			next:  node4,
+		}
+
+		assert.Same(t, node2, Intersection(listA, listB))
+		assert.Same(t, node4, Intersection(listB, listA))
+	})
+
+	t.Run("Returns shared node given lists of different lengths", func(t *testing.T) {
+		sharedTail := newLinkedListWithCycle(3, 0)
+		listA := &Node[int]{
+			value: 10,
+			next:  sharedTail,
+		}
+		listB := &Node[int]{
+			value: 20,
+			next: &Node[int]{
+				value: 21,
+				next: &Node[int]{
+					value: 22,
+					next:  sharedTail,
+				},
+			},
+		}
+
+		assert.Equal(t, sharedTail, Intersection(listA, listB))
+		assert.Equal(t, sharedTail, Intersection(listB, listA))
+	})
+
+	t.Run("Returns head given same list", func(t *testing.T) {
+		list := newLinkedListWithCycle(3, 2)
+
+		assert.Equal(t, list, Intersection(list, list))
+	})
+
+	t.Run("Returns nil given only one list with loop", func(t *testing.T) {
+		listA := newLinkedListWithCycle(3, 2)
+		listB := newLinkedListWithCycle(3, 0)
+
+		assert.Nil(t, Intersection(listA, listB))
+	})
+
+	t.Run("Returns nil given separate lists with loops", func(t *testing.T) {
+		listA := newLinkedListWithCycle(3, 2)
+		listB := newLinkedListWithCycle(1, 4)
+
+		assert.Nil(t, Intersection(listA, listB))
+	})
+
+	t.Run("Returns shared node before loop", func(t *testing.T) {
+		sharedTail := newLinkedListWithCycle(2, 3)
+		listA := &Node[int]{
+			value: 10,
+			next:  sharedTail,
+		}
+		listB := &Node[int]{
+			value: 20,
+			next: &Node[int]{
+				value: 21,
+				next:  sharedTail,
+			},
+		}
+
+		assert.Equal(t, sharedTail, Intersection(listA, listB))
+	})
+
+	t.Run("Returns loop entry of first list given lists joining the loop at different nodes", func(t *testing.T) {
+		loop := newLinkedListWithCycle(0, 4)
+		listA := &Node[int]{
+			value: 10,
+			next:  loop,
+		}
+		listB := &Node[int]{
+			value: 20,
+			next:  loop.next.next,
+		}
+
+		assert.Equal(t, loop, Intersection(listA, listB))
+		assert.Equal(t, loop.next.next, Intersection(listB, listA))
+	})
+}
+
+func newLinkedListWithCycle(tailLength, cycleLength int) *Node[int] {
+	var head *Node[int]
+	var last *Node[int]
+	var entry *Node[int]
+
+	for i := 0; i < tailLength+cycleLength; i++ {
+		node := &Node[int]{
+			value: i,
+		}
+		if head == nil {
+			head = node
+		} else {
+			last.next = node
+		}
+		if i == tailLength {
+			entry = node
+		}
+		last = node
+	}
+
+	if last != nil {
+		last.next = entry
+	}
+	return head
+}
+
 func TestIsPalindrom(t *testing.T) {
 
 	t.Run("Not palindrom", func(t *testing.T) {
@@ -568,5 +1102,205 @@ func TestIsPalindrom(t *testing.T) {
 
 		assert.True(t, isPalindrom)
 	})
+	t.Run("Even length palindrom", func(t *testing.T) {
+		list := Node[rune]{
+			value: 'A',
+			next: &Node[rune]{
+				value: 'B',
+				next: &Node[rune]{
+					value: 'B',
+					next: &Node[rune]{
+						value: 'A',
+						next:  nil,
+					},
+				},
+			},
+		}
+
+		isPalindrom := isPalindrom(&list)
+
+		assert.True(t, isPalindrom)
+	})
+
+	t.Run("Restores list after check", func(t *testing.T) {
+		list := Node[rune]{
+			value: 'K',
+			next: &Node[rune]{
+				value: 'A',
+				next: &Node[rune]{
+					value: 'Y',
+					next: &Node[rune]{
+						value: 'A',
+						next: &Node[rune]{
+							value: 'Z',
+							next:  nil,
+						},
+					},
+				},
+			},
+		}
+		originalList := copyLinkedList(&list)
+
+		_ = isPalindrom(&list)
+
+		assert.Equal(t, originalList, &list)
+	})
+
+	t.Run("Single element", func(t *testing.T) {
+		list := Node[rune]{
+			value: 'K',
+			next:  nil,
+		}
+
+		assert.True(t, isPalindrom(&list))
+	})
+}
+
+func TestBigNumber(t *testing.T) {
+	t.Run("Parses and prints number given decimal string", func(t *testing.T) {
+		number, err := newBigNumberFromString("-00123", 10)
+		assert.Nil(t, err)
+		assert.Equal(t, "-123", number.String())
+		assert.Equal(t, big.NewInt(-123), number.BigInt())
+	})
+
+	t.Run("Error given digit outside of base", func(t *testing.T) {
+		number, err := newBigNumberFromString("12a", 10)
+		assert.Nil(t, number)
+		assert.NotNil(t, err)
+	})
+
+	t.Run("Error given invalid base", func(t *testing.T) {
+		number, err := newBigNumber(12, 1)
+		assert.Nil(t, number)
+		assert.NotNil(t, err)
+	})
+
+	t.Run("Negative zero is zero", func(t *testing.T) {
+		number, _ := newBigNumberFromString("-000", 10)
+		assert.Equal(t, "0", number.String())
+		assert.Equal(t, 0, number.Sign())
+	})
+
+	t.Run("999+1=1000", func(t *testing.T) {
+		numberA, _ := newBigNumberFromString("999", 10)
+		numberB, _ := newBigNumber(1, 10)
+		assert.Equal(t, "1000", numberA.Add(numberB).String())
+	})
 
+	t.Run("Add handles mixed signs", func(t *testing.T) {
+		numberA, _ := newBigNumber(-617, 10)
+		numberB, _ := newBigNumber(295, 10)
+		assert.Equal(t, "-322", numberA.Add(numberB).String())
+		assert.Equal(t, "322", numberB.Negate().Add(numberA.Negate()).String())
+	})
+
+	t.Run("1000-1=999", func(t *testing.T) {
+		numberA, _ := newBigNumber(1000, 10)
+		numberB, _ := newBigNumber(1, 10)
+		assert.Equal(t, "999", numberA.Subtract(numberB).String())
+		assert.Equal(t, "-999", numberB.Subtract(numberA).String())
+		assert.Equal(t, "0", numberA.Subtract(numberA).String())
+	})
+
+	t.Run("Multiply matches big.Int", func(t *testing.T) {
+		numberA, _ := newBigNumberFromString("-123456789012345678901234567890", 10)
+		numberB, _ := newBigNumberFromString("987654321098765432109876543210", 10)
+
+		expected := new(big.Int).Mul(numberA.BigInt(), numberB.BigInt())
+
+		assert.Equal(t, expected.String(), numberA.Multiply(numberB).String())
+	})
+
+	t.Run("Multiply by zero gives zero", func(t *testing.T) {
+		numberA, _ := newBigNumber(-42, 10)
+		numberB, _ := newBigNumber(0, 10)
+		assert.Equal(t, "0", numberA.Multiply(numberB).String())
+	})
+
+	t.Run("Compare orders numbers", func(t *testing.T) {
+		small, _ := newBigNumber(-1000, 10)
+		medium, _ := newBigNumber(99, 10)
+		large, _ := newBigNumber(100, 10)
+
+		assert.Equal(t, -1, small.Compare(medium))
+		assert.Equal(t, 1, large.Compare(medium))
+		assert.Equal(t, 0, medium.Compare(medium))
+	})
+
+	t.Run("Works in hexadecimal base", func(t *testing.T) {
+		numberA, _ := newBigNumberFromString("FF", 16)
+		numberB, _ := newBigNumberFromString("1", 16)
+		assert.Equal(t, "100", numberA.Add(numberB).String())
+		assert.Equal(t, big.NewInt(256), numberA.Add(numberB).BigInt())
+	})
+
+	t.Run("Converts other number to receiver base", func(t *testing.T) {
+		binary, _ := newBigNumberFromString("1010", 2)
+		decimal, _ := newBigNumber(5, 10)
+
+		sum := binary.Add(decimal)
+		assert.Equal(t, 2, sum.Base())
+		assert.Equal(t, "1111", sum.String())
+		assert.Equal(t, 1, binary.Compare(decimal))
+	})
+
+	t.Run("Round trips through big.Int", func(t *testing.T) {
+		value, _ := new(big.Int).SetString("-31415926535897932384626433832795028841971", 10)
+		number, err := newBigNumberFromBigInt(value, 7)
+		assert.Nil(t, err)
+		assert.Equal(t, value.Text(7), number.String())
+		assert.Equal(t, value, number.BigInt())
+	})
+}
+
+func TestSumInRadix(t *testing.T) {
+	t.Run("0xFF+0x01=0x100 in base 16", func(t *testing.T) {
+		numberA := Node[uint8]{
+			value: 0xF,
+			next: &Node[uint8]{
+				value: 0xF,
+				next:  nil,
+			},
+		}
+
+		numberB := Node[uint8]{
+			value: 0x1,
+			next:  nil,
+		}
+
+		actualSum, err := sumInRadix(&numberA, &numberB, 16)
+
+		expectedSum := &Node[uint8]{
+			value: 0x1,
+			next: &Node[uint8]{
+				value: 0x0,
+				next: &Node[uint8]{
+					value: 0x0,
+					next:  nil,
+				},
+			},
+		}
+
+		assert.Nil(t, err)
+		assert.Equal(t, expectedSum, actualSum)
+	})
+
+	t.Run("Error reports position from the most significant digit", func(t *testing.T) {
+		numberA := Node[int]{
+			value: 1,
+			next: &Node[int]{
+				value: 2,
+				next:  nil,
+			},
+		}
+
+		actualSum, err := sumInRadix(&numberA, nil, 2)
+
+		var digitErr *InvalidDigitError[int]
+		assert.Nil(t, actualSum)
+		assert.True(t, errors.As(err, &digitErr))
+		assert.Equal(t, 1, digitErr.Position)
+		assert.Equal(t, 2, digitErr.Radix)
+	})
 }
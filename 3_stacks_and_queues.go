@@ -1,15 +1,47 @@
 package main
 
 import (
+	"bufio"
+	"bytes"
 	"constraints"
+	"context"
+	"encoding/gob"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+	"unsafe"
 )
 
-type Stack[T constraints.Ordered] struct {
-	last *Node[T]
+type StackInterface[T any] interface {
+	Push(value T)
+	Peek() (T, bool)
+	TryPop() (T, bool)
+	IsEmpty() bool
 }
 
-func newStack[T constraints.Ordered]() *Stack[T] {
+var (
+	_ StackInterface[int] = (*Stack[int])(nil)
+	_ StackInterface[int] = (*SliceStack[int])(nil)
+	_ StackInterface[int] = (*SyncStack[int])(nil)
+	_ StackInterface[int] = (*TreiberStack[int])(nil)
+	_ StackInterface[int] = (*MinMaxStack[int])(nil)
+	_ StackInterface[int] = (*DequeStack[int])(nil)
+)
+
+type Stack[T any] struct {
+	last   *Node[T]
+	length int
+}
+
+func newStack[T any]() *Stack[T] {
 	return &Stack[T]{}
 }
 
@@ -24,347 +56,2942 @@ func (stack *Stack[T]) Push(value T) {
 			next:  stack.last,
 		}
 	}
+	stack.length++
 }
 
-func (stack *Stack[T]) Pick() *T {
+func (stack *Stack[T]) Peek() (T, bool) {
 	if stack.last != nil {
-		return &stack.last.value
+		return stack.last.value, true
 	}
-	return nil
+	var zero T
+	return zero, false
 }
 
-func (stack *Stack[T]) Pop() *T {
+func (stack *Stack[T]) TryPop() (T, bool) {
 	if stack.last != nil {
 		lastValue := stack.last.value
 		stack.last = stack.last.next
-		return &lastValue
+		stack.length--
+		return lastValue, true
 	}
-	return nil
+	var zero T
+	return zero, false
+}
+
+// Deprecated: use Peek instead.
+func (stack *Stack[T]) Pick() *T {
+	return pointerOf(stack.Peek())
+}
+
+// Deprecated: use TryPop instead.
+func (stack *Stack[T]) Pop() *T {
+	return pointerOf(stack.TryPop())
 }
 
 func (stack *Stack[T]) IsEmpty() bool {
-	lastElement := stack.Pick()
-	return lastElement == nil
+	return stack.last == nil
 }
 
-type Queue[T constraints.Ordered] struct {
-	first *Node[T]
+func (stack *Stack[T]) Len() int {
+	return stack.length
 }
 
-func newQueue[T constraints.Ordered]() *Queue[T] {
-	return &Queue[T]{}
+func (stack *Stack[T]) Clear() {
+	stack.last = nil
+	stack.length = 0
 }
 
-func (queue *Queue[T]) Pick() *T {
-	if queue.first != nil {
-		return &queue.first.value
+func (stack *Stack[T]) removeBottom() (T, bool) {
+	if stack.last == nil || stack.last.next == nil {
+		return stack.TryPop()
 	}
-	return nil
+
+	previous := stack.last
+	for previous.next.next != nil {
+		previous = previous.next
+	}
+
+	bottomValue := previous.next.value
+	previous.next = nil
+	stack.length--
+	return bottomValue, true
 }
 
-func (queue *Queue[T]) Enqueue(value T) {
-	if queue.first == nil {
-		queue.first = &Node[T]{
-			value: value,
-		}
-	} else {
-		current := queue.first
-		for current.next != nil {
-			current = current.next
-		}
-		current.next = &Node[T]{
-			value: value,
-		}
+type SetOfStacks[T any] struct {
+	stacks   []*Stack[T]
+	capacity int
+}
+
+func newSetOfStacks[T any](capacity int) (*SetOfStacks[T], error) {
+	if capacity < 1 {
+		return nil, errors.New("Capacity of single stack must be at least 1")
+	}
+
+	return &SetOfStacks[T]{
+		capacity: capacity,
+	}, nil
+}
+
+func (set *SetOfStacks[T]) Push(value T) {
+	lastStack := set.lastStack()
+	if lastStack == nil || lastStack.Len() == set.capacity {
+		lastStack = newStack[T]()
+		set.stacks = append(set.stacks, lastStack)
 	}
+	lastStack.Push(value)
 }
 
-func (queue *Queue[T]) Dequeue() *T {
-	if queue.first != nil {
-		firstValue := queue.first.value
-		queue.first = queue.first.next
-		return &firstValue
+func (set *SetOfStacks[T]) Peek() (T, bool) {
+	if lastStack := set.lastStack(); lastStack != nil {
+		return lastStack.Peek()
 	}
-	return nil
+	var zero T
+	return zero, false
 }
 
-type MinStack[T constraints.Ordered] struct {
-	valueStack   *Stack[T]
-	minimumStack *Stack[T]
+func (set *SetOfStacks[T]) TryPop() (T, bool) {
+	return set.PopAt(len(set.stacks) - 1)
 }
 
-func newMinStack[T constraints.Ordered]() *MinStack[T] {
-	return &MinStack[T]{
-		valueStack:   newStack[T](),
-		minimumStack: newStack[T](),
+func (set *SetOfStacks[T]) PopAt(index int) (T, bool) {
+	if index < 0 || index >= len(set.stacks) {
+		var zero T
+		return zero, false
+	}
+
+	value, _ := set.stacks[index].TryPop()
+	for next := index + 1; next < len(set.stacks); next++ {
+		bottomValue, _ := set.stacks[next].removeBottom()
+		set.stacks[next-1].Push(bottomValue)
+	}
+
+	if lastIndex := len(set.stacks) - 1; set.stacks[lastIndex].IsEmpty() {
+		set.stacks[lastIndex] = nil
+		set.stacks = set.stacks[:lastIndex]
 	}
+	return value, true
 }
 
-func (minStack *MinStack[T]) Push(value T) {
-	minStack.valueStack.Push(value)
-	if currentMinimum := minStack.minimumStack.Pick(); currentMinimum == nil || (*currentMinimum) > value {
-		minStack.minimumStack.Push(value)
+func (set *SetOfStacks[T]) IsEmpty() bool {
+	return len(set.stacks) == 0
+}
+
+func (set *SetOfStacks[T]) Len() int {
+	length := 0
+	for _, stack := range set.stacks {
+		length += stack.Len()
 	}
+	return length
 }
 
-func (minStack *MinStack[T]) Pick() *T {
-	return minStack.valueStack.Pick()
+func (set *SetOfStacks[T]) StackCount() int {
+	return len(set.stacks)
 }
 
-func (minStack *MinStack[T]) Pop() *T {
-	lastValue := minStack.valueStack.Pop()
+func (set *SetOfStacks[T]) lastStack() *Stack[T] {
+	if len(set.stacks) == 0 {
+		return nil
+	}
+	return set.stacks[len(set.stacks)-1]
+}
+
+func SortStack[T constraints.Ordered](stack *Stack[T]) {
+	Sort[T](stack, func(a, b T) bool {
+		return a < b
+	})
+}
+
+func Sort[T any](stack StackInterface[T], less func(a, b T) bool) {
+	sortedStack := newStack[T]()
+
+	for current, hasValue := stack.TryPop(); hasValue; current, hasValue = stack.TryPop() {
+		for {
+			sortedTop, hasTop := sortedStack.Peek()
+			if !hasTop || !less(current, sortedTop) {
+				break
+			}
 
-	if *lastValue == *minStack.minimumStack.Pick() {
-		_ = minStack.minimumStack.Pop()
+			_, _ = sortedStack.TryPop()
+			stack.Push(sortedTop)
+		}
+		sortedStack.Push(current)
 	}
 
-	return lastValue
+	for value, hasValue := sortedStack.TryPop(); hasValue; value, hasValue = sortedStack.TryPop() {
+		stack.Push(value)
+	}
 }
 
-func (minStack *MinStack[T]) Min() *T {
-	return minStack.minimumStack.Pick()
+var ErrMultiStackFull = errors.New("All stacks are full")
+
+type multiStackInfo struct {
+	start    int
+	size     int
+	capacity int
 }
 
-type HanoiGame struct {
-	height         int
-	sourceRod      *Stack[int]
-	spareRod       *Stack[int]
-	destinationRod *Stack[int]
+type MultiStack[T any] struct {
+	values []T
+	stacks []*multiStackInfo
 }
 
-func newHanoiGame(height int) (*HanoiGame, error) {
-	if height < 1 {
-		return nil, errors.New("Min Height for hanoi tower is 3")
+func newMultiStack[T any](numberOfStacks, defaultCapacity int) (*MultiStack[T], error) {
+	if numberOfStacks < 1 {
+		return nil, errors.New("Number of stacks must be at least 1")
 	}
-	game := &HanoiGame{
-		height:         height,
-		sourceRod:      newStack[int](),
-		spareRod:       newStack[int](),
-		destinationRod: newStack[int](),
+	if defaultCapacity < 1 {
+		return nil, errors.New("Capacity of single stack must be at least 1")
 	}
 
-	for i := height; i >= 1; i-- {
-		game.sourceRod.Push(i)
+	multiStack := &MultiStack[T]{
+		values: make([]T, numberOfStacks*defaultCapacity),
+		stacks: make([]*multiStackInfo, numberOfStacks),
+	}
+	for index := range multiStack.stacks {
+		multiStack.stacks[index] = &multiStackInfo{
+			start:    index * defaultCapacity,
+			capacity: defaultCapacity,
+		}
 	}
 
-	return game, nil
+	return multiStack, nil
 }
 
-func (game *HanoiGame) Solve() {
-	Move(game.height, game.sourceRod, game.destinationRod, game.spareRod)
+func (multiStack *MultiStack[T]) Push(stackIndex int, value T) error {
+	if err := multiStack.validateStackIndex(stackIndex); err != nil {
+		return err
+	}
+	if multiStack.isFull() {
+		return ErrMultiStackFull
+	}
+
+	stack := multiStack.stacks[stackIndex]
+	if stack.size == stack.capacity {
+		multiStack.shift((stackIndex + 1) % len(multiStack.stacks))
+		stack.capacity++
+	}
+
+	stack.size++
+	multiStack.values[multiStack.lastElementIndex(stack)] = value
+	return nil
 }
 
-func Move(numberOfDisks int, source, destination, spare *Stack[int]) {
-	if numberOfDisks == 1 {
-		Swap(source, destination)
-	} else {
-		Move(numberOfDisks-1, source, spare, destination)
-		Move(1, source, destination, spare)
-		Move(numberOfDisks-1, spare, destination, source)
+func (multiStack *MultiStack[T]) Peek(stackIndex int) (T, bool) {
+	var zero T
+	if multiStack.IsEmpty(stackIndex) {
+		return zero, false
 	}
+
+	stack := multiStack.stacks[stackIndex]
+	return multiStack.values[multiStack.lastElementIndex(stack)], true
 }
 
-func Swap(source, destination *Stack[int]) {
-	element := source.Pop()
-	destination.Push(*element)
+func (multiStack *MultiStack[T]) TryPop(stackIndex int) (T, bool) {
+	var zero T
+	if multiStack.IsEmpty(stackIndex) {
+		return zero, false
+	}
+
+	stack := multiStack.stacks[stackIndex]
+	lastIndex := multiStack.lastElementIndex(stack)
+	value := multiStack.values[lastIndex]
+	multiStack.values[lastIndex] = zero
+	stack.size--
+	return value, true
 }
 
-type QueueOnTwoStacks[T constraints.Ordered] struct {
-	newestElements *Stack[T]
-	oldestElements *Stack[T]
+func (multiStack *MultiStack[T]) IsEmpty(stackIndex int) bool {
+	return multiStack.Len(stackIndex) == 0
 }
 
-func newQueueOnTwoStacks[T constraints.Ordered]() *QueueOnTwoStacks[T] {
-	return &QueueOnTwoStacks[T]{
-		newestElements: newStack[T](),
-		oldestElements: newStack[T](),
+func (multiStack *MultiStack[T]) Len(stackIndex int) int {
+	if multiStack.validateStackIndex(stackIndex) != nil {
+		return 0
 	}
+	return multiStack.stacks[stackIndex].size
 }
 
-func (queue *QueueOnTwoStacks[T]) Pick() *T {
-	shiftStacks(queue.newestElements, queue.oldestElements)
-	return queue.oldestElements.Pick()
+func (multiStack *MultiStack[T]) validateStackIndex(stackIndex int) error {
+	if stackIndex < 0 || stackIndex >= len(multiStack.stacks) {
+		return fmt.Errorf("Stack index %d is out of range", stackIndex)
+	}
+	return nil
 }
 
-func (queue *QueueOnTwoStacks[T]) Enqueue(value T) {
-	queue.newestElements.Push(value)
+func (multiStack *MultiStack[T]) isFull() bool {
+	size := 0
+	for _, stack := range multiStack.stacks {
+		size += stack.size
+	}
+	return size == len(multiStack.values)
 }
 
-func (queue *QueueOnTwoStacks[T]) Dequeue() *T {
-	shiftStacks(queue.newestElements, queue.oldestElements)
-	return queue.oldestElements.Pop()
-}
+func (multiStack *MultiStack[T]) shift(stackIndex int) {
+	stack := multiStack.stacks[stackIndex]
+	if stack.size >= stack.capacity {
+		multiStack.shift((stackIndex + 1) % len(multiStack.stacks))
+		stack.capacity++
+	}
 
-func shiftStacks[T constraints.Ordered](from, to *Stack[T]) {
-	if !to.IsEmpty() {
-		return
+	index := multiStack.adjustIndex(stack.start + stack.capacity - 1)
+	for multiStack.isWithinCapacity(stack, index) {
+		previous := multiStack.adjustIndex(index - 1)
+		multiStack.values[index] = multiStack.values[previous]
+		index = previous
 	}
 
-	for fromElement := from.Pop(); fromElement != nil; fromElement = from.Pop() {
-		to.Push(*fromElement)
+	var zero T
+	multiStack.values[stack.start] = zero
+	stack.start = multiStack.adjustIndex(stack.start + 1)
+	stack.capacity--
+}
+
+func (multiStack *MultiStack[T]) isWithinCapacity(stack *multiStackInfo, index int) bool {
+	if index < stack.start {
+		index += len(multiStack.values)
 	}
+	return stack.start < index && index < stack.start+stack.capacity
 }
 
-type MaxStack[T constraints.Ordered] struct {
-	top *Node[T]
+func (multiStack *MultiStack[T]) lastElementIndex(stack *multiStackInfo) int {
+	return multiStack.adjustIndex(stack.start + stack.size - 1)
 }
 
-func newMaxStack[T constraints.Ordered]() *MaxStack[T] {
-	return &MaxStack[T]{}
+func (multiStack *MultiStack[T]) adjustIndex(index int) int {
+	length := len(multiStack.values)
+	return ((index % length) + length) % length
 }
 
-func (stack *MaxStack[T]) Push(value T) {
-	if stack.top == nil || value >= stack.top.value {
-		stack.top = &Node[T]{
-			value: value,
-			next:  stack.top,
-		}
-	} else {
-		previous := stack.top
-		current := previous.next
-		for current != nil && current.value > value {
-			previous = current
-			current = previous.next
-		}
+type SliceStack[T any] struct {
+	values []T
+}
 
-		newNode := &Node[T]{
-			value: value,
-			next:  current,
-		}
-		previous.next = newNode
+func newSliceStack[T any]() *SliceStack[T] {
+	return &SliceStack[T]{}
+}
+
+func newSliceStackWithCapacity[T any](capacity int) *SliceStack[T] {
+	return &SliceStack[T]{
+		values: make([]T, 0, capacity),
 	}
 }
 
-func (stack *MaxStack[T]) Pick() *T {
-	if stack.top != nil {
-		return &stack.top.value
+func (stack *SliceStack[T]) Push(value T) {
+	stack.values = append(stack.values, value)
+}
+
+func (stack *SliceStack[T]) Peek() (T, bool) {
+	if len(stack.values) != 0 {
+		return stack.values[len(stack.values)-1], true
 	}
-	return nil
+	var zero T
+	return zero, false
 }
 
-func (stack *MaxStack[T]) Pop() *T {
-	if stack.top != nil {
-		lastValue := stack.top.value
-		stack.top = stack.top.next
-		return &lastValue
+func (stack *SliceStack[T]) TryPop() (T, bool) {
+	lastIndex := len(stack.values) - 1
+	if lastIndex < 0 {
+		var zero T
+		return zero, false
 	}
-	return nil
+
+	lastValue := stack.values[lastIndex]
+	var zero T
+	stack.values[lastIndex] = zero
+	stack.values = stack.values[:lastIndex]
+	return lastValue, true
 }
 
-func (stack *MaxStack[T]) IsEmpty() bool {
-	lastElement := stack.Pick()
-	return lastElement == nil
+func (stack *SliceStack[T]) IsEmpty() bool {
+	return len(stack.values) == 0
 }
 
-type IAnimal interface {
-	GetName() string
+func (stack *SliceStack[T]) Len() int {
+	return len(stack.values)
 }
 
-type Animal struct {
-	Name string
+func (stack *SliceStack[T]) Cap() int {
+	return cap(stack.values)
 }
 
-func (animal Animal) GetName() string {
-	return animal.Name
+func (stack *SliceStack[T]) Reserve(capacity int) {
+	if capacity <= cap(stack.values) {
+		return
+	}
+
+	values := make([]T, len(stack.values), capacity)
+	copy(values, stack.values)
+	stack.values = values
 }
 
-type Cat struct {
-	Animal
+func (stack *SliceStack[T]) Shrink() {
+	if len(stack.values) == cap(stack.values) {
+		return
+	}
+
+	values := make([]T, len(stack.values))
+	copy(values, stack.values)
+	stack.values = values
 }
 
-type Dog struct {
-	Animal
+type QueueInterface[T any] interface {
+	Enqueue(value T)
+	Peek() (T, bool)
+	TryDequeue() (T, bool)
+	IsEmpty() bool
 }
 
-func newCat(name string) *Cat {
-	return &Cat{
-		Animal{
-			Name: name,
-		},
+var (
+	_ QueueInterface[int] = (*Queue[int])(nil)
+	_ QueueInterface[int] = (*RingQueue[int])(nil)
+	_ QueueInterface[int] = (*QueueOnTwoStacks[int])(nil)
+	_ QueueInterface[int] = (*MinMaxQueue[int])(nil)
+	_ QueueInterface[int] = (*DequeQueue[int])(nil)
+	_ QueueInterface[int] = (*SyncQueue[int])(nil)
+	_ QueueInterface[int] = (*MichaelScottQueue[int])(nil)
+)
+
+type Queue[T any] struct {
+	first  *Node[T]
+	last   *Node[T]
+	length int
+}
+
+func newQueue[T any]() *Queue[T] {
+	return &Queue[T]{}
+}
+
+func (queue *Queue[T]) Peek() (T, bool) {
+	if queue.first != nil {
+		return queue.first.value, true
 	}
+	var zero T
+	return zero, false
 }
 
-func newDog(name string) *Dog {
-	return &Dog{
-		Animal{
-			Name: name,
-		},
+func (queue *Queue[T]) Enqueue(value T) {
+	node := &Node[T]{
+		value: value,
+	}
+
+	if queue.first == nil {
+		queue.first = node
+	} else {
+		queue.last.next = node
 	}
+	queue.last = node
+	queue.length++
 }
 
-type Shelter struct {
-	first *Node[IAnimal]
+func (queue *Queue[T]) TryDequeue() (T, bool) {
+	if queue.first != nil {
+		firstValue := queue.first.value
+		queue.first = queue.first.next
+		if queue.first == nil {
+			queue.last = nil
+		}
+		queue.length--
+		return firstValue, true
+	}
+	var zero T
+	return zero, false
 }
 
-func newShelter() *Shelter {
-	return &Shelter{}
+// Deprecated: use Peek instead.
+func (queue *Queue[T]) Pick() *T {
+	return pointerOf(queue.Peek())
+}
+
+// Deprecated: use TryDequeue instead.
+func (queue *Queue[T]) Dequeue() *T {
+	return pointerOf(queue.TryDequeue())
+}
+
+func (queue *Queue[T]) IsEmpty() bool {
+	return queue.first == nil
+}
+
+func (queue *Queue[T]) Len() int {
+	return queue.length
+}
+
+func (queue *Queue[T]) Clear() {
+	queue.first = nil
+	queue.last = nil
+	queue.length = 0
 }
 
-func (shelter *Shelter) Pick() IAnimal {
-	if shelter.first != nil {
-		return shelter.first.value
+var (
+	ErrQueueClosed = errors.New("Queue is closed")
+	ErrQueueFull   = errors.New("Queue is full")
+	ErrQueueEmpty  = errors.New("Queue is empty")
+)
+
+type BlockingQueue[T any] struct {
+	mutex    sync.Mutex
+	queue    *Queue[T]
+	capacity int
+	closed   bool
+	changed  chan struct{}
+}
+
+func newBlockingQueue[T any](capacity int) (*BlockingQueue[T], error) {
+	if capacity < 1 {
+		return nil, errors.New("Capacity of blocking queue must be at least 1")
 	}
-	return nil
+
+	return &BlockingQueue[T]{
+		queue:    newQueue[T](),
+		capacity: capacity,
+		changed:  make(chan struct{}),
+	}, nil
 }
 
-func (shelter *Shelter) Enqueue(value IAnimal) {
-	if shelter.first == nil {
-		shelter.first = &Node[IAnimal]{
-			value: value,
+func (queue *BlockingQueue[T]) Put(ctx context.Context, value T) error {
+	for {
+		queue.mutex.Lock()
+		err := queue.tryPutLocked(value)
+		changed := queue.changed
+		queue.mutex.Unlock()
+
+		if err != ErrQueueFull {
+			return err
 		}
-	} else {
-		current := shelter.first
-		for current.next != nil {
-			current = current.next
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-changed:
 		}
+	}
+}
 
-		current.next = &Node[IAnimal]{
-			value: value,
+func (queue *BlockingQueue[T]) TryPut(value T) error {
+	queue.mutex.Lock()
+	defer queue.mutex.Unlock()
+	return queue.tryPutLocked(value)
+}
+
+func (queue *BlockingQueue[T]) Take(ctx context.Context) (T, error) {
+	for {
+		queue.mutex.Lock()
+		value, err := queue.tryTakeLocked()
+		changed := queue.changed
+		queue.mutex.Unlock()
+
+		if err != ErrQueueEmpty {
+			return value, err
 		}
+
+		select {
+		case <-ctx.Done():
+			var zero T
+			return zero, ctx.Err()
+		case <-changed:
+		}
+	}
+}
+
+func (queue *BlockingQueue[T]) TryTake() (T, error) {
+	queue.mutex.Lock()
+	defer queue.mutex.Unlock()
+	return queue.tryTakeLocked()
+}
+
+func (queue *BlockingQueue[T]) Close() {
+	queue.mutex.Lock()
+	defer queue.mutex.Unlock()
+
+	if !queue.closed {
+		queue.closed = true
+		queue.notifyLocked()
 	}
 }
 
-func (shelter *Shelter) DequeueAny() IAnimal {
-	if shelter.first != nil {
-		firstValue := shelter.first.value
-		shelter.first = shelter.first.next
-		return firstValue
+func (queue *BlockingQueue[T]) Drain() []T {
+	queue.mutex.Lock()
+	defer queue.mutex.Unlock()
+
+	values := make([]T, 0, queue.queue.Len())
+	for value, hasValue := queue.queue.TryDequeue(); hasValue; value, hasValue = queue.queue.TryDequeue() {
+		values = append(values, value)
+	}
+	queue.notifyLocked()
+
+	return values
+}
+
+func (queue *BlockingQueue[T]) Len() int {
+	queue.mutex.Lock()
+	defer queue.mutex.Unlock()
+	return queue.queue.Len()
+}
+
+func (queue *BlockingQueue[T]) Cap() int {
+	return queue.capacity
+}
+
+func (queue *BlockingQueue[T]) IsClosed() bool {
+	queue.mutex.Lock()
+	defer queue.mutex.Unlock()
+	return queue.closed
+}
+
+func (queue *BlockingQueue[T]) tryPutLocked(value T) error {
+	if queue.closed {
+		return ErrQueueClosed
+	}
+	if queue.queue.Len() == queue.capacity {
+		return ErrQueueFull
 	}
+
+	queue.queue.Enqueue(value)
+	queue.notifyLocked()
 	return nil
 }
 
-func (shelter *Shelter) DequeueDog() *Dog {
-	animal := DequeueSpecific[*Dog](shelter)
+func (queue *BlockingQueue[T]) tryTakeLocked() (T, error) {
+	if queue.queue.IsEmpty() {
+		var zero T
+		if queue.closed {
+			return zero, ErrQueueClosed
+		}
+		return zero, ErrQueueEmpty
+	}
+
+	value, _ := queue.queue.TryDequeue()
+	queue.notifyLocked()
+	return value, nil
+}
 
-	dog, _ := animal.(*Dog)
-	return dog
+func (queue *BlockingQueue[T]) notifyLocked() {
+	close(queue.changed)
+	queue.changed = make(chan struct{})
 }
 
-func (shelter *Shelter) DequeueCat() *Cat {
-	animal := DequeueSpecific[*Cat](shelter)
+type RingQueue[T any] struct {
+	values []T
+	head   int
+	count  int
+}
 
-	cat, _ :=  animal.(*Cat)
-	return cat
+func newRingQueue[T any]() *RingQueue[T] {
+	return &RingQueue[T]{}
 }
 
-func DequeueSpecific[T IAnimal](shelter *Shelter) IAnimal {
-	if animal, isSpecificAnimal := shelter.first.value.(T); isSpecificAnimal {
-		shelter.first = shelter.first.next
-		return animal
+func newRingQueueWithCapacity[T any](capacity int) *RingQueue[T] {
+	return &RingQueue[T]{
+		values: make([]T, capacity),
 	}
+}
 
-	previousAnimal := shelter.first
-	currentAnimal := previousAnimal.next
+func (queue *RingQueue[T]) Peek() (T, bool) {
+	if queue.count != 0 {
+		return queue.values[queue.head], true
+	}
+	var zero T
+	return zero, false
+}
 
-	for currentAnimal != nil {
+func (queue *RingQueue[T]) Enqueue(value T) {
+	if queue.count == len(queue.values) {
+		queue.grow()
+	}
 
-		if animal, isSpecificAnimal := currentAnimal.value.(T); isSpecificAnimal {
-			previousAnimal.next = currentAnimal.next
-			return animal
-		}
+	queue.values[(queue.head+queue.count)%len(queue.values)] = value
+	queue.count++
+}
 
-		previousAnimal = currentAnimal
-		currentAnimal = previousAnimal.next
+func (queue *RingQueue[T]) TryDequeue() (T, bool) {
+	var zero T
+	if queue.count == 0 {
+		return zero, false
 	}
 
-	return nil
-}
\ No newline at end of file
+	firstValue := queue.values[queue.head]
+	queue.values[queue.head] = zero
+	queue.head = (queue.head + 1) % len(queue.values)
+	queue.count--
+	return firstValue, true
+}
+
+func (queue *RingQueue[T]) IsEmpty() bool {
+	return queue.count == 0
+}
+
+func (queue *RingQueue[T]) Len() int {
+	return queue.count
+}
+
+func (queue *RingQueue[T]) Cap() int {
+	return len(queue.values)
+}
+
+func (queue *RingQueue[T]) grow() {
+	capacity := 2 * len(queue.values)
+	if capacity == 0 {
+		capacity = 4
+	}
+
+	values := make([]T, capacity)
+	for i := 0; i < queue.count; i++ {
+		values[i] = queue.values[(queue.head+i)%len(queue.values)]
+	}
+
+	queue.values = values
+	queue.head = 0
+}
+
+const dequeChunkSize = 64
+
+type Deque[T any] struct {
+	chunks [][]T
+	head   int
+	length int
+}
+
+func newDeque[T any]() *Deque[T] {
+	return &Deque[T]{}
+}
+
+func (deque *Deque[T]) PushBack(value T) {
+	if deque.head+deque.length == len(deque.chunks)*dequeChunkSize {
+		deque.grow()
+	}
+
+	deque.length++
+	*deque.slot(deque.length - 1) = value
+}
+
+func (deque *Deque[T]) PushFront(value T) {
+	if deque.head == 0 {
+		deque.grow()
+	}
+
+	deque.head--
+	deque.length++
+	*deque.slot(0) = value
+}
+
+func (deque *Deque[T]) PopBack() (T, bool) {
+	var zero T
+	if deque.length == 0 {
+		return zero, false
+	}
+
+	position := deque.head + deque.length - 1
+	slot := deque.slot(deque.length - 1)
+	value := *slot
+	*slot = zero
+	deque.length--
+
+	if position%dequeChunkSize == 0 {
+		deque.chunks[position/dequeChunkSize] = nil
+	}
+	return value, true
+}
+
+func (deque *Deque[T]) PopFront() (T, bool) {
+	var zero T
+	if deque.length == 0 {
+		return zero, false
+	}
+
+	position := deque.head
+	slot := deque.slot(0)
+	value := *slot
+	*slot = zero
+	deque.head++
+	deque.length--
+
+	if position%dequeChunkSize == dequeChunkSize-1 {
+		deque.chunks[position/dequeChunkSize] = nil
+	}
+	return value, true
+}
+
+func (deque *Deque[T]) PeekBack() (T, bool) {
+	return deque.At(deque.length - 1)
+}
+
+func (deque *Deque[T]) PeekFront() (T, bool) {
+	return deque.At(0)
+}
+
+func (deque *Deque[T]) At(index int) (T, bool) {
+	if index < 0 || index >= deque.length {
+		var zero T
+		return zero, false
+	}
+	return *deque.slot(index), true
+}
+
+func (deque *Deque[T]) Set(index int, value T) bool {
+	if index < 0 || index >= deque.length {
+		return false
+	}
+
+	*deque.slot(index) = value
+	return true
+}
+
+func (deque *Deque[T]) Len() int {
+	return deque.length
+}
+
+func (deque *Deque[T]) IsEmpty() bool {
+	return deque.length == 0
+}
+
+func (deque *Deque[T]) slot(index int) *T {
+	position := deque.head + index
+	chunk := deque.chunks[position/dequeChunkSize]
+	if chunk == nil {
+		chunk = make([]T, dequeChunkSize)
+		deque.chunks[position/dequeChunkSize] = chunk
+	}
+	return &chunk[position%dequeChunkSize]
+}
+
+func (deque *Deque[T]) grow() {
+	firstChunk := deque.head / dequeChunkSize
+	usedChunks := 0
+	if deque.length != 0 {
+		usedChunks = (deque.head+deque.length-1)/dequeChunkSize - firstChunk + 1
+	}
+
+	chunkCount := 2*usedChunks + 2
+	if chunkCount < 4 {
+		chunkCount = 4
+	}
+	if chunkCount < len(deque.chunks) {
+		chunkCount = len(deque.chunks)
+	}
+
+	chunks := make([][]T, chunkCount)
+	newFirstChunk := (chunkCount - usedChunks) / 2
+	copy(chunks[newFirstChunk:], deque.chunks[firstChunk:firstChunk+usedChunks])
+
+	deque.chunks = chunks
+	deque.head = newFirstChunk*dequeChunkSize + deque.head%dequeChunkSize
+}
+
+type DequeStack[T any] struct {
+	deque *Deque[T]
+}
+
+func newDequeStack[T any]() *DequeStack[T] {
+	return &DequeStack[T]{
+		deque: newDeque[T](),
+	}
+}
+
+func (stack *DequeStack[T]) Push(value T) {
+	stack.deque.PushBack(value)
+}
+
+func (stack *DequeStack[T]) Peek() (T, bool) {
+	return stack.deque.PeekBack()
+}
+
+func (stack *DequeStack[T]) TryPop() (T, bool) {
+	return stack.deque.PopBack()
+}
+
+func (stack *DequeStack[T]) IsEmpty() bool {
+	return stack.deque.IsEmpty()
+}
+
+type DequeQueue[T any] struct {
+	deque *Deque[T]
+}
+
+func newDequeQueue[T any]() *DequeQueue[T] {
+	return &DequeQueue[T]{
+		deque: newDeque[T](),
+	}
+}
+
+func (queue *DequeQueue[T]) Enqueue(value T) {
+	queue.deque.PushBack(value)
+}
+
+func (queue *DequeQueue[T]) Peek() (T, bool) {
+	return queue.deque.PeekFront()
+}
+
+func (queue *DequeQueue[T]) TryDequeue() (T, bool) {
+	return queue.deque.PopFront()
+}
+
+func (queue *DequeQueue[T]) IsEmpty() bool {
+	return queue.deque.IsEmpty()
+}
+
+func pointerOf[T any](value T, ok bool) *T {
+	if !ok {
+		return nil
+	}
+	return &value
+}
+
+type MinStack[T constraints.Ordered] struct {
+	valueStack   *Stack[T]
+	minimumStack *Stack[T]
+}
+
+func newMinStack[T constraints.Ordered]() *MinStack[T] {
+	return &MinStack[T]{
+		valueStack:   newStack[T](),
+		minimumStack: newStack[T](),
+	}
+}
+
+func (minStack *MinStack[T]) Push(value T) {
+	minStack.valueStack.Push(value)
+	if currentMinimum, hasMinimum := minStack.minimumStack.Peek(); !hasMinimum || currentMinimum >= value {
+		minStack.minimumStack.Push(value)
+	}
+}
+
+func (minStack *MinStack[T]) Peek() (T, bool) {
+	return minStack.valueStack.Peek()
+}
+
+func (minStack *MinStack[T]) TryPop() (T, bool) {
+	lastValue, hasValue := minStack.valueStack.TryPop()
+	if !hasValue {
+		return lastValue, false
+	}
+
+	if currentMinimum, _ := minStack.minimumStack.Peek(); lastValue == currentMinimum {
+		_, _ = minStack.minimumStack.TryPop()
+	}
+
+	return lastValue, true
+}
+
+func (minStack *MinStack[T]) TryMin() (T, bool) {
+	return minStack.minimumStack.Peek()
+}
+
+// Deprecated: use Peek instead.
+func (minStack *MinStack[T]) Pick() *T {
+	return pointerOf(minStack.Peek())
+}
+
+// Deprecated: use TryPop instead.
+func (minStack *MinStack[T]) Pop() *T {
+	return pointerOf(minStack.TryPop())
+}
+
+// Deprecated: use TryMin instead.
+func (minStack *MinStack[T]) Min() *T {
+	return pointerOf(minStack.TryMin())
+}
+
+func (minStack *MinStack[T]) IsEmpty() bool {
+	return minStack.valueStack.IsEmpty()
+}
+
+func (minStack *MinStack[T]) Len() int {
+	return minStack.valueStack.Len()
+}
+
+func (minStack *MinStack[T]) Clear() {
+	minStack.valueStack.Clear()
+	minStack.minimumStack.Clear()
+}
+
+type MinMaxStack[T constraints.Ordered] struct {
+	valueStack   *Stack[T]
+	minimumStack *Stack[T]
+	maximumStack *Stack[T]
+}
+
+func newMinMaxStack[T constraints.Ordered]() *MinMaxStack[T] {
+	return &MinMaxStack[T]{
+		valueStack:   newStack[T](),
+		minimumStack: newStack[T](),
+		maximumStack: newStack[T](),
+	}
+}
+
+func (stack *MinMaxStack[T]) Push(value T) {
+	stack.valueStack.Push(value)
+	if currentMinimum, hasMinimum := stack.minimumStack.Peek(); !hasMinimum || currentMinimum >= value {
+		stack.minimumStack.Push(value)
+	}
+	if currentMaximum, hasMaximum := stack.maximumStack.Peek(); !hasMaximum || currentMaximum <= value {
+		stack.maximumStack.Push(value)
+	}
+}
+
+func (stack *MinMaxStack[T]) Peek() (T, bool) {
+	return stack.valueStack.Peek()
+}
+
+func (stack *MinMaxStack[T]) TryPop() (T, bool) {
+	lastValue, hasValue := stack.valueStack.TryPop()
+	if !hasValue {
+		return lastValue, false
+	}
+
+	if currentMinimum, _ := stack.minimumStack.Peek(); lastValue == currentMinimum {
+		_, _ = stack.minimumStack.TryPop()
+	}
+	if currentMaximum, _ := stack.maximumStack.Peek(); lastValue == currentMaximum {
+		_, _ = stack.maximumStack.TryPop()
+	}
+
+	return lastValue, true
+}
+
+func (stack *MinMaxStack[T]) TryMin() (T, bool) {
+	return stack.minimumStack.Peek()
+}
+
+func (stack *MinMaxStack[T]) TryMax() (T, bool) {
+	return stack.maximumStack.Peek()
+}
+
+func (stack *MinMaxStack[T]) IsEmpty() bool {
+	return stack.valueStack.IsEmpty()
+}
+
+func (stack *MinMaxStack[T]) Len() int {
+	return stack.valueStack.Len()
+}
+
+func (stack *MinMaxStack[T]) Clear() {
+	stack.valueStack.Clear()
+	stack.minimumStack.Clear()
+	stack.maximumStack.Clear()
+}
+
+type HanoiGame struct {
+	height         int
+	sourceRod      *Stack[int]
+	spareRod       *Stack[int]
+	destinationRod *Stack[int]
+	undoMoves      *Stack[HanoiMove]
+	redoMoves      *Stack[HanoiMove]
+}
+
+func newHanoiGame(height int) (*HanoiGame, error) {
+	if height < 1 {
+		return nil, errors.New("Min Height for hanoi tower is 3")
+	}
+	game := &HanoiGame{
+		height:         height,
+		sourceRod:      newStack[int](),
+		spareRod:       newStack[int](),
+		destinationRod: newStack[int](),
+		undoMoves:      newStack[HanoiMove](),
+		redoMoves:      newStack[HanoiMove](),
+	}
+
+	for i := height; i >= 1; i-- {
+		game.sourceRod.Push(i)
+	}
+
+	return game, nil
+}
+
+type HanoiRod int
+
+const (
+	SourceRod HanoiRod = iota
+	SpareRod
+	DestinationRod
+)
+
+func (rod HanoiRod) String() string {
+	switch rod {
+	case SourceRod:
+		return "source"
+	case SpareRod:
+		return "spare"
+	case DestinationRod:
+		return "destination"
+	default:
+		return fmt.Sprintf("HanoiRod(%d)", int(rod))
+	}
+}
+
+type HanoiMove struct {
+	From HanoiRod
+	To   HanoiRod
+}
+
+var (
+	ErrHanoiInvalidRod  = errors.New("Rod does not exist")
+	ErrHanoiEmptyRod    = errors.New("Cannot move disk from empty rod")
+	ErrHanoiLargerDisk  = errors.New("Cannot place larger disk on smaller disk")
+	ErrHanoiSameRodMove = errors.New("Cannot move disk onto the same rod")
+	ErrHanoiNoUndo      = errors.New("No move to undo")
+	ErrHanoiNoRedo      = errors.New("No move to redo")
+	ErrHanoiNotAtStart  = errors.New("Game can only be solved from its start position")
+)
+
+type hanoiFrame struct {
+	disks int
+	move  HanoiMove
+	spare HanoiRod
+}
+
+func HanoiMoves(height int, emit func(HanoiMove)) int {
+	if height < 1 {
+		return 0
+	}
+
+	frames := newStack[hanoiFrame]()
+	frames.Push(hanoiFrame{disks: height, move: HanoiMove{From: SourceRod, To: DestinationRod}, spare: SpareRod})
+
+	moves := 0
+	for frame, ok := frames.TryPop(); ok; frame, ok = frames.TryPop() {
+		if frame.disks == 1 {
+			moves++
+			if emit != nil {
+				emit(frame.move)
+			}
+			continue
+		}
+
+		from, to, spare := frame.move.From, frame.move.To, frame.spare
+		frames.Push(hanoiFrame{disks: frame.disks - 1, move: HanoiMove{From: spare, To: to}, spare: from})
+		frames.Push(hanoiFrame{disks: 1, move: HanoiMove{From: from, To: to}, spare: spare})
+		frames.Push(hanoiFrame{disks: frame.disks - 1, move: HanoiMove{From: from, To: spare}, spare: to})
+	}
+
+	return moves
+}
+
+func (game *HanoiGame) Solve() (int, error) {
+	return game.SolveWith(nil)
+}
+
+func (game *HanoiGame) SolveWith(emit func(HanoiMove)) (int, error) {
+	if game.sourceRod.Len() != game.height {
+		return 0, ErrHanoiNotAtStart
+	}
+
+	moves := 0
+	var err error
+	HanoiMoves(game.height, func(move HanoiMove) {
+		if err != nil {
+			return
+		}
+		if err = game.MoveDisk(move.From, move.To); err != nil {
+			return
+		}
+		moves++
+		if emit != nil {
+			emit(move)
+		}
+	})
+	return moves, err
+}
+
+func (game *HanoiGame) MoveDisk(from, to HanoiRod) error {
+	move := HanoiMove{From: from, To: to}
+	if err := game.applyMove(move); err != nil {
+		return err
+	}
+	game.undoMoves.Push(move)
+	game.redoMoves.Clear()
+	return nil
+}
+
+func (game *HanoiGame) Undo() error {
+	move, hasMove := game.undoMoves.TryPop()
+	if !hasMove {
+		return ErrHanoiNoUndo
+	}
+	Swap(game.rod(move.To), game.rod(move.From))
+	game.redoMoves.Push(move)
+	return nil
+}
+
+func (game *HanoiGame) Redo() error {
+	move, hasMove := game.redoMoves.TryPop()
+	if !hasMove {
+		return ErrHanoiNoRedo
+	}
+	Swap(game.rod(move.From), game.rod(move.To))
+	game.undoMoves.Push(move)
+	return nil
+}
+
+func (game *HanoiGame) IsSolved() bool {
+	return game.sourceRod.IsEmpty() && game.spareRod.IsEmpty() && game.destinationRod.Len() == game.height
+}
+
+func (game *HanoiGame) MoveCount() int {
+	return game.undoMoves.Len()
+}
+
+func (game *HanoiGame) String() string {
+	var builder strings.Builder
+	for _, rod := range []HanoiRod{SourceRod, SpareRod, DestinationRod} {
+		fmt.Fprintf(&builder, "%d |", int(rod)+1)
+		for _, disk := range rodDisks(game.rod(rod)) {
+			fmt.Fprintf(&builder, " %d", disk)
+		}
+		builder.WriteString("\n")
+	}
+	return builder.String()
+}
+
+func rodDisks(rod *Stack[int]) []int {
+	disks := make([]int, rod.Len())
+	i := len(disks) - 1
+	for node := rod.last; node != nil; node = node.next {
+		disks[i] = node.value
+		i--
+	}
+	return disks
+}
+
+func (game *HanoiGame) Replay(moves []HanoiMove) error {
+	scratch := game.copyRods()
+	for i, move := range moves {
+		if err := scratch.applyMove(move); err != nil {
+			return fmt.Errorf("Move %d from %v to %v: %w", i, move.From, move.To, err)
+		}
+	}
+
+	for _, move := range moves {
+		_ = game.MoveDisk(move.From, move.To)
+	}
+	return nil
+}
+
+func (game *HanoiGame) copyRods() *HanoiGame {
+	return &HanoiGame{
+		height:         game.height,
+		sourceRod:      copyRod(game.sourceRod),
+		spareRod:       copyRod(game.spareRod),
+		destinationRod: copyRod(game.destinationRod),
+	}
+}
+
+func copyRod(rod *Stack[int]) *Stack[int] {
+	copied := newStack[int]()
+	for _, disk := range rodDisks(rod) {
+		copied.Push(disk)
+	}
+	return copied
+}
+
+func (game *HanoiGame) applyMove(move HanoiMove) error {
+	source, destination := game.rod(move.From), game.rod(move.To)
+	if source == nil || destination == nil {
+		return ErrHanoiInvalidRod
+	}
+	if move.From == move.To {
+		return ErrHanoiSameRodMove
+	}
+
+	disk, hasDisk := source.Peek()
+	if !hasDisk {
+		return ErrHanoiEmptyRod
+	}
+	if top, hasTop := destination.Peek(); hasTop && top < disk {
+		return ErrHanoiLargerDisk
+	}
+
+	Swap(source, destination)
+	return nil
+}
+
+func (game *HanoiGame) rod(rod HanoiRod) *Stack[int] {
+	switch rod {
+	case SourceRod:
+		return game.sourceRod
+	case SpareRod:
+		return game.spareRod
+	case DestinationRod:
+		return game.destinationRod
+	default:
+		return nil
+	}
+}
+
+func Swap(source, destination *Stack[int]) {
+	if element, hasElement := source.TryPop(); hasElement {
+		destination.Push(element)
+	}
+}
+
+const hanoiHelp = `Move disks from rod 1 to rod 3, never placing a larger disk on a smaller one.
+Commands:
+  <from> <to>  move top disk, rods are 1-3 or source, spare, destination
+  u, undo      undo last move
+  r, redo      redo undone move
+  h, help      show this help
+  q, quit      leave the game
+`
+
+func playHanoi(game *HanoiGame, input io.Reader, output io.Writer) error {
+	scanner := bufio.NewScanner(input)
+	fmt.Fprint(output, hanoiHelp)
+
+	for {
+		fmt.Fprint(output, game)
+		if game.IsSolved() {
+			fmt.Fprintf(output, "Solved in %d moves\n", game.MoveCount())
+			return nil
+		}
+
+		fmt.Fprint(output, "> ")
+		if !scanner.Scan() {
+			return scanner.Err()
+		}
+
+		var err error
+		fields := strings.Fields(strings.ToLower(scanner.Text()))
+		switch {
+		case len(fields) == 0:
+			continue
+		case fields[0] == "q" || fields[0] == "quit":
+			return nil
+		case fields[0] == "h" || fields[0] == "help":
+			fmt.Fprint(output, hanoiHelp)
+		case fields[0] == "u" || fields[0] == "undo":
+			err = game.Undo()
+		case fields[0] == "r" || fields[0] == "redo":
+			err = game.Redo()
+		case len(fields) == 2:
+			err = moveHanoiDisk(game, fields[0], fields[1])
+		default:
+			err = fmt.Errorf("Unknown command %q", scanner.Text())
+		}
+
+		if err != nil {
+			fmt.Fprintf(output, "Error: %v\n", err)
+		}
+	}
+}
+
+func moveHanoiDisk(game *HanoiGame, from, to string) error {
+	source, err := parseHanoiRod(from)
+	if err != nil {
+		return err
+	}
+	destination, err := parseHanoiRod(to)
+	if err != nil {
+		return err
+	}
+	return game.MoveDisk(source, destination)
+}
+
+func parseHanoiRod(text string) (HanoiRod, error) {
+	for _, rod := range []HanoiRod{SourceRod, SpareRod, DestinationRod} {
+		if text == rod.String() || text == fmt.Sprint(int(rod)+1) {
+			return rod, nil
+		}
+	}
+	return 0, fmt.Errorf("%w: %s", ErrHanoiInvalidRod, text)
+}
+
+type QueueOnTwoStacks[T any] struct {
+	newestElements *Stack[T]
+	oldestElements *Stack[T]
+}
+
+func newQueueOnTwoStacks[T any]() *QueueOnTwoStacks[T] {
+	return &QueueOnTwoStacks[T]{
+		newestElements: newStack[T](),
+		oldestElements: newStack[T](),
+	}
+}
+
+func (queue *QueueOnTwoStacks[T]) Peek() (T, bool) {
+	queue.shift()
+	return queue.oldestElements.Peek()
+}
+
+func (queue *QueueOnTwoStacks[T]) Enqueue(value T) {
+	queue.newestElements.Push(value)
+}
+
+func (queue *QueueOnTwoStacks[T]) TryDequeue() (T, bool) {
+	queue.shift()
+	return queue.oldestElements.TryPop()
+}
+
+// Deprecated: use Peek instead.
+func (queue *QueueOnTwoStacks[T]) Pick() *T {
+	return pointerOf(queue.Peek())
+}
+
+// Deprecated: use TryDequeue instead.
+func (queue *QueueOnTwoStacks[T]) Dequeue() *T {
+	return pointerOf(queue.TryDequeue())
+}
+
+func (queue *QueueOnTwoStacks[T]) IsEmpty() bool {
+	return queue.newestElements.IsEmpty() && queue.oldestElements.IsEmpty()
+}
+
+func (queue *QueueOnTwoStacks[T]) Len() int {
+	return queue.newestElements.Len() + queue.oldestElements.Len()
+}
+
+func (queue *QueueOnTwoStacks[T]) Clear() {
+	queue.newestElements.Clear()
+	queue.oldestElements.Clear()
+}
+
+func (queue *QueueOnTwoStacks[T]) shift() {
+	if !queue.oldestElements.IsEmpty() {
+		return
+	}
+
+	for value, hasValue := queue.newestElements.TryPop(); hasValue; value, hasValue = queue.newestElements.TryPop() {
+		queue.oldestElements.Push(value)
+	}
+}
+
+func shiftStacks[T any](from, to StackInterface[T]) {
+	if !to.IsEmpty() {
+		return
+	}
+
+	for value, hasValue := from.TryPop(); hasValue; value, hasValue = from.TryPop() {
+		to.Push(value)
+	}
+}
+
+type MinMaxQueue[T constraints.Ordered] struct {
+	newestElements *MinMaxStack[T]
+	oldestElements *MinMaxStack[T]
+}
+
+func newMinMaxQueue[T constraints.Ordered]() *MinMaxQueue[T] {
+	return &MinMaxQueue[T]{
+		newestElements: newMinMaxStack[T](),
+		oldestElements: newMinMaxStack[T](),
+	}
+}
+
+func (queue *MinMaxQueue[T]) Peek() (T, bool) {
+	shiftStacks[T](queue.newestElements, queue.oldestElements)
+	return queue.oldestElements.Peek()
+}
+
+func (queue *MinMaxQueue[T]) Enqueue(value T) {
+	queue.newestElements.Push(value)
+}
+
+func (queue *MinMaxQueue[T]) TryDequeue() (T, bool) {
+	shiftStacks[T](queue.newestElements, queue.oldestElements)
+	return queue.oldestElements.TryPop()
+}
+
+func (queue *MinMaxQueue[T]) IsEmpty() bool {
+	return queue.newestElements.IsEmpty() && queue.oldestElements.IsEmpty()
+}
+
+func (queue *MinMaxQueue[T]) Len() int {
+	return queue.newestElements.Len() + queue.oldestElements.Len()
+}
+
+func (queue *MinMaxQueue[T]) Clear() {
+	queue.newestElements.Clear()
+	queue.oldestElements.Clear()
+}
+
+func (queue *MinMaxQueue[T]) TryMin() (T, bool) {
+	newestMinimum, hasNewest := queue.newestElements.TryMin()
+	oldestMinimum, hasOldest := queue.oldestElements.TryMin()
+	if !hasNewest || (hasOldest && oldestMinimum < newestMinimum) {
+		return oldestMinimum, hasOldest
+	}
+	return newestMinimum, true
+}
+
+func (queue *MinMaxQueue[T]) TryMax() (T, bool) {
+	newestMaximum, hasNewest := queue.newestElements.TryMax()
+	oldestMaximum, hasOldest := queue.oldestElements.TryMax()
+	if !hasNewest || (hasOldest && oldestMaximum > newestMaximum) {
+		return oldestMaximum, hasOldest
+	}
+	return newestMaximum, true
+}
+
+func SlidingWindow[T constraints.Ordered](values []T, size int) ([]T, []T, error) {
+	if size < 1 {
+		return nil, nil, errors.New("Window size must be at least 1")
+	}
+
+	minima := []T{}
+	maxima := []T{}
+	window := newMinMaxQueue[T]()
+
+	for index, value := range values {
+		window.Enqueue(value)
+		if index >= size {
+			_, _ = window.TryDequeue()
+		}
+
+		if index >= size-1 {
+			minimum, _ := window.TryMin()
+			maximum, _ := window.TryMax()
+			minima = append(minima, minimum)
+			maxima = append(maxima, maximum)
+		}
+	}
+
+	return minima, maxima, nil
+}
+
+type maxStackEntry[T constraints.Ordered] struct {
+	value    T
+	sequence int
+	below    *maxStackEntry[T]
+	above    *maxStackEntry[T]
+	heapItem *HeapItem[*maxStackEntry[T]]
+}
+
+type MaxStack[T constraints.Ordered] struct {
+	top          *maxStackEntry[T]
+	maximumHeap  *IndexedHeap[*maxStackEntry[T]]
+	nextSequence int
+}
+
+func newMaxStack[T constraints.Ordered]() *MaxStack[T] {
+	return &MaxStack[T]{
+		maximumHeap: newIndexedHeap(func(a, b *maxStackEntry[T]) bool {
+			if a.value == b.value {
+				return a.sequence > b.sequence
+			}
+			return a.value > b.value
+		}),
+	}
+}
+
+func (stack *MaxStack[T]) Push(value T) {
+	entry := &maxStackEntry[T]{
+		value:    value,
+		sequence: stack.nextSequence,
+		below:    stack.top,
+	}
+	stack.nextSequence++
+
+	if stack.top != nil {
+		stack.top.above = entry
+	}
+	stack.top = entry
+	entry.heapItem = stack.maximumHeap.Push(entry)
+}
+
+func (stack *MaxStack[T]) Peek() (T, bool) {
+	if stack.top != nil {
+		return stack.top.value, true
+	}
+	var zero T
+	return zero, false
+}
+
+func (stack *MaxStack[T]) TryPop() (T, bool) {
+	if stack.top == nil {
+		var zero T
+		return zero, false
+	}
+
+	entry := stack.top
+	_ = stack.maximumHeap.Remove(entry.heapItem)
+	stack.unlink(entry)
+
+	return entry.value, true
+}
+
+func (stack *MaxStack[T]) TryMax() (T, bool) {
+	if maximum := stack.maximumHeap.Pick(); maximum != nil {
+		return maximum.value.value, true
+	}
+	var zero T
+	return zero, false
+}
+
+func (stack *MaxStack[T]) TryPopMax() (T, bool) {
+	maximum := stack.maximumHeap.Pop()
+	if maximum == nil {
+		var zero T
+		return zero, false
+	}
+
+	entry := maximum.value
+	stack.unlink(entry)
+
+	return entry.value, true
+}
+
+// Deprecated: use Peek instead.
+func (stack *MaxStack[T]) Pick() *T {
+	return pointerOf(stack.Peek())
+}
+
+// Deprecated: use TryPop instead.
+func (stack *MaxStack[T]) Pop() *T {
+	return pointerOf(stack.TryPop())
+}
+
+// Deprecated: use TryMax instead.
+func (stack *MaxStack[T]) Max() *T {
+	return pointerOf(stack.TryMax())
+}
+
+func (stack *MaxStack[T]) IsEmpty() bool {
+	return stack.top == nil
+}
+
+func (stack *MaxStack[T]) Len() int {
+	return stack.maximumHeap.Len()
+}
+
+func (stack *MaxStack[T]) Clear() {
+	stack.top = nil
+	stack.maximumHeap = newIndexedHeap(stack.maximumHeap.compare)
+}
+
+func (stack *MaxStack[T]) unlink(entry *maxStackEntry[T]) {
+	if entry.above != nil {
+		entry.above.below = entry.below
+	} else {
+		stack.top = entry.below
+	}
+
+	if entry.below != nil {
+		entry.below.above = entry.above
+	}
+
+	entry.above = nil
+	entry.below = nil
+}
+
+var ErrHeapItemNotFound = errors.New("Item does not belong to heap")
+
+type heapOrder interface {
+	length() int
+	less(i, j int) bool
+	swap(i, j int)
+}
+
+func siftUp(heap heapOrder, arity, index int) {
+	for index > 0 {
+		parent := (index - 1) / arity
+		if !heap.less(index, parent) {
+			return
+		}
+		heap.swap(index, parent)
+		index = parent
+	}
+}
+
+func siftDown(heap heapOrder, arity, index int) {
+	length := heap.length()
+	for {
+		smallest := index
+		firstChild := arity*index + 1
+		for child := firstChild; child < firstChild+arity && child < length; child++ {
+			if heap.less(child, smallest) {
+				smallest = child
+			}
+		}
+
+		if smallest == index {
+			return
+		}
+		heap.swap(index, smallest)
+		index = smallest
+	}
+}
+
+func validateHeapArity(arity int) error {
+	if arity < 2 {
+		return errors.New("Heap arity must be at least 2")
+	}
+	return nil
+}
+
+type PriorityQueue[T any] struct {
+	values  []T
+	compare func(a, b T) bool
+	arity   int
+}
+
+func newPriorityQueue[T any](less func(a, b T) bool) *PriorityQueue[T] {
+	queue, _ := newDaryPriorityQueue(2, less)
+	return queue
+}
+
+func newDaryPriorityQueue[T any](arity int, less func(a, b T) bool) (*PriorityQueue[T], error) {
+	if err := validateHeapArity(arity); err != nil {
+		return nil, err
+	}
+
+	return &PriorityQueue[T]{
+		compare: less,
+		arity:   arity,
+	}, nil
+}
+
+func newMinPriorityQueue[T constraints.Ordered]() *PriorityQueue[T] {
+	return newPriorityQueue(func(a, b T) bool {
+		return a < b
+	})
+}
+
+func newMaxPriorityQueue[T constraints.Ordered]() *PriorityQueue[T] {
+	return newPriorityQueue(func(a, b T) bool {
+		return a > b
+	})
+}
+
+func (queue *PriorityQueue[T]) Push(value T) {
+	queue.values = append(queue.values, value)
+	siftUp(queue, queue.arity, len(queue.values)-1)
+}
+
+func (queue *PriorityQueue[T]) Peek() (T, bool) {
+	if len(queue.values) != 0 {
+		return queue.values[0], true
+	}
+	var zero T
+	return zero, false
+}
+
+func (queue *PriorityQueue[T]) TryPop() (T, bool) {
+	var zero T
+	lastIndex := len(queue.values) - 1
+	if lastIndex < 0 {
+		return zero, false
+	}
+
+	topValue := queue.values[0]
+	queue.swap(0, lastIndex)
+	queue.values[lastIndex] = zero
+	queue.values = queue.values[:lastIndex]
+	siftDown(queue, queue.arity, 0)
+
+	return topValue, true
+}
+
+func (queue *PriorityQueue[T]) IsEmpty() bool {
+	return len(queue.values) == 0
+}
+
+func (queue *PriorityQueue[T]) Len() int {
+	return len(queue.values)
+}
+
+func (queue *PriorityQueue[T]) length() int {
+	return len(queue.values)
+}
+
+func (queue *PriorityQueue[T]) less(i, j int) bool {
+	return queue.compare(queue.values[i], queue.values[j])
+}
+
+func (queue *PriorityQueue[T]) swap(i, j int) {
+	queue.values[i], queue.values[j] = queue.values[j], queue.values[i]
+}
+
+type HeapItem[T any] struct {
+	value T
+	index int
+}
+
+func (item *HeapItem[T]) Value() T {
+	return item.value
+}
+
+type IndexedHeap[T any] struct {
+	items   []*HeapItem[T]
+	compare func(a, b T) bool
+	arity   int
+}
+
+func newIndexedHeap[T any](less func(a, b T) bool) *IndexedHeap[T] {
+	heap, _ := newDaryIndexedHeap(2, less)
+	return heap
+}
+
+func newDaryIndexedHeap[T any](arity int, less func(a, b T) bool) (*IndexedHeap[T], error) {
+	if err := validateHeapArity(arity); err != nil {
+		return nil, err
+	}
+
+	return &IndexedHeap[T]{
+		compare: less,
+		arity:   arity,
+	}, nil
+}
+
+func (heap *IndexedHeap[T]) Push(value T) *HeapItem[T] {
+	item := &HeapItem[T]{
+		value: value,
+		index: len(heap.items),
+	}
+	heap.items = append(heap.items, item)
+	siftUp(heap, heap.arity, item.index)
+
+	return item
+}
+
+func (heap *IndexedHeap[T]) Pick() *HeapItem[T] {
+	if len(heap.items) != 0 {
+		return heap.items[0]
+	}
+	return nil
+}
+
+func (heap *IndexedHeap[T]) Pop() *HeapItem[T] {
+	if len(heap.items) == 0 {
+		return nil
+	}
+
+	item := heap.items[0]
+	heap.removeAt(0)
+	return item
+}
+
+func (heap *IndexedHeap[T]) Update(item *HeapItem[T], value T) error {
+	if !heap.Contains(item) {
+		return ErrHeapItemNotFound
+	}
+
+	item.value = value
+	siftUp(heap, heap.arity, item.index)
+	siftDown(heap, heap.arity, item.index)
+	return nil
+}
+
+func (heap *IndexedHeap[T]) Remove(item *HeapItem[T]) error {
+	if !heap.Contains(item) {
+		return ErrHeapItemNotFound
+	}
+
+	heap.removeAt(item.index)
+	return nil
+}
+
+func (heap *IndexedHeap[T]) Contains(item *HeapItem[T]) bool {
+	return item != nil && item.index >= 0 && item.index < len(heap.items) && heap.items[item.index] == item
+}
+
+func (heap *IndexedHeap[T]) IsEmpty() bool {
+	return len(heap.items) == 0
+}
+
+func (heap *IndexedHeap[T]) Len() int {
+	return len(heap.items)
+}
+
+func (heap *IndexedHeap[T]) removeAt(index int) {
+	lastIndex := len(heap.items) - 1
+	item := heap.items[index]
+
+	heap.swap(index, lastIndex)
+	heap.items[lastIndex] = nil
+	heap.items = heap.items[:lastIndex]
+	item.index = -1
+
+	if index < lastIndex {
+		siftUp(heap, heap.arity, index)
+		siftDown(heap, heap.arity, index)
+	}
+}
+
+func (heap *IndexedHeap[T]) length() int {
+	return len(heap.items)
+}
+
+func (heap *IndexedHeap[T]) less(i, j int) bool {
+	return heap.compare(heap.items[i].value, heap.items[j].value)
+}
+
+func (heap *IndexedHeap[T]) swap(i, j int) {
+	heap.items[i], heap.items[j] = heap.items[j], heap.items[i]
+	heap.items[i].index = i
+	heap.items[j].index = j
+}
+
+type AnimalSize int
+
+const (
+	SizeUnknown AnimalSize = iota
+	SizeSmall
+	SizeMedium
+	SizeLarge
+)
+
+var animalSizeNames = map[AnimalSize]string{
+	SizeUnknown: "unknown",
+	SizeSmall:   "small",
+	SizeMedium:  "medium",
+	SizeLarge:   "large",
+}
+
+func (size AnimalSize) String() string {
+	if name, ok := animalSizeNames[size]; ok {
+		return name
+	}
+	return fmt.Sprintf("AnimalSize(%d)", int(size))
+}
+
+func (size AnimalSize) MarshalText() ([]byte, error) {
+	if _, ok := animalSizeNames[size]; !ok {
+		return nil, fmt.Errorf("Invalid animal size %d", int(size))
+	}
+	return []byte(size.String()), nil
+}
+
+func (size *AnimalSize) UnmarshalText(text []byte) error {
+	if len(text) == 0 {
+		*size = SizeUnknown
+		return nil
+	}
+	for value, name := range animalSizeNames {
+		if name == string(text) {
+			*size = value
+			return nil
+		}
+	}
+	return fmt.Errorf("Invalid animal size %q", text)
+}
+
+type IAnimal interface {
+	GetName() string
+	GetAge() int
+	GetBreed() string
+	GetSize() AnimalSize
+	GetIntakeDate() time.Time
+}
+
+// IntakeDate is when the animal came into care, which can predate its
+// arrival in this shelter (for example after a transfer). Shelter.Admit
+// fills it from the arrival time when it is left zero.
+type Animal struct {
+	Name       string     `json:"name"`
+	Age        int        `json:"age"`
+	Breed      string     `json:"breed"`
+	Size       AnimalSize `json:"size"`
+	IntakeDate time.Time  `json:"intakeDate"`
+}
+
+func (animal Animal) GetName() string {
+	return animal.Name
+}
+
+func (animal Animal) GetAge() int {
+	return animal.Age
+}
+
+func (animal Animal) GetBreed() string {
+	return animal.Breed
+}
+
+func (animal Animal) GetSize() AnimalSize {
+	return animal.Size
+}
+
+func (animal Animal) GetIntakeDate() time.Time {
+	return animal.IntakeDate
+}
+
+func (animal *Animal) SetIntakeDate(date time.Time) {
+	animal.IntakeDate = date
+}
+
+type Cat struct {
+	Animal
+}
+
+type Dog struct {
+	Animal
+}
+
+func newCat(name string) *Cat {
+	return &Cat{
+		Animal{
+			Name: name,
+		},
+	}
+}
+
+func newDog(name string) *Dog {
+	return &Dog{
+		Animal{
+			Name: name,
+		},
+	}
+}
+
+var (
+	ErrShelterEmpty      = errors.New("No animal of requested species in shelter")
+	ErrUnknownSpecies    = errors.New("Species is not registered in shelter")
+	ErrSpeciesRegistered = errors.New("Species is already registered in shelter")
+	ErrNoMatchingAnimal  = errors.New("No animal matching criteria in shelter")
+	ErrInvalidSpecies    = errors.New("Species needs a name and a prototype")
+	ErrReservedSpecies   = errors.New("Species name is reserved")
+	ErrNilAnimal         = errors.New("Animal must not be nil")
+	ErrNilPredicate      = errors.New("Predicate must not be nil")
+)
+
+type ShelterEntry struct {
+	Animal    IAnimal
+	Species   string
+	Sequence  uint64
+	ArrivedAt time.Time
+}
+
+// Adopted records stay in the other queue until they reach its head or
+// outnumber the waiting ones, so adopting from either end never has to
+// search the middle of a queue.
+type shelterRecord struct {
+	entry   ShelterEntry
+	adopted bool
+}
+
+type shelterQueue struct {
+	records *Queue[*shelterRecord]
+	waiting int
+}
+
+func newShelterQueue() *shelterQueue {
+	return &shelterQueue{
+		records: newQueue[*shelterRecord](),
+	}
+}
+
+func (queue *shelterQueue) push(record *shelterRecord) {
+	queue.records.Enqueue(record)
+	queue.waiting++
+}
+
+func (queue *shelterQueue) first() (*shelterRecord, bool) {
+	for record, hasRecord := queue.records.Peek(); hasRecord; record, hasRecord = queue.records.Peek() {
+		if !record.adopted {
+			return record, true
+		}
+		_, _ = queue.records.TryDequeue()
+	}
+	return nil, false
+}
+
+func (queue *shelterQueue) remove() {
+	queue.waiting--
+	_, _ = queue.first()
+	if queue.records.Len() > 2*queue.waiting {
+		queue.compact()
+	}
+}
+
+func (queue *shelterQueue) compact() {
+	records := newQueue[*shelterRecord]()
+	queue.each(func(record *shelterRecord) bool {
+		records.Enqueue(record)
+		return true
+	})
+	queue.records = records
+}
+
+func (queue *shelterQueue) each(visit func(record *shelterRecord) bool) {
+	for node := queue.records.first; node != nil; node = node.next {
+		if !node.value.adopted && !visit(node.value) {
+			return
+		}
+	}
+}
+
+type AdoptionRecord struct {
+	ShelterEntry
+	AdoptedAt time.Time
+}
+
+type AdoptionQuery struct {
+	Species string
+	Name    string
+	Since   time.Time
+	Until   time.Time
+}
+
+func (query AdoptionQuery) matches(record AdoptionRecord) bool {
+	if query.Species != "" && query.Species != record.Species {
+		return false
+	}
+	if query.Name != "" && query.Name != record.Animal.GetName() {
+		return false
+	}
+	if !query.Since.IsZero() && record.AdoptedAt.Before(query.Since) {
+		return false
+	}
+	if !query.Until.IsZero() && !record.AdoptedAt.Before(query.Until) {
+		return false
+	}
+	return true
+}
+
+type Shelter struct {
+	speciesByType map[reflect.Type]string
+	typeBySpecies map[string]reflect.Type
+	species       map[string]*shelterQueue
+	arrivals      *shelterQueue
+	adoptions     []AdoptionRecord
+	nextSequence  uint64
+	now           func() time.Time
+}
+
+func newShelter() *Shelter {
+	return &Shelter{
+		speciesByType: make(map[reflect.Type]string),
+		typeBySpecies: make(map[string]reflect.Type),
+		species:       make(map[string]*shelterQueue),
+		arrivals:      newShelterQueue(),
+		now:           time.Now,
+	}
+}
+
+func (shelter *Shelter) RegisterSpecies(name string, prototype IAnimal) error {
+	if name == "" || prototype == nil {
+		return ErrInvalidSpecies
+	}
+	if name == anySpecies {
+		return ErrReservedSpecies
+	}
+
+	animalType := reflect.TypeOf(prototype)
+	if _, isRegistered := shelter.species[name]; isRegistered {
+		return ErrSpeciesRegistered
+	}
+	if _, isRegistered := shelter.speciesByType[animalType]; isRegistered {
+		return ErrSpeciesRegistered
+	}
+
+	shelter.speciesByType[animalType] = name
+	shelter.typeBySpecies[name] = animalType
+	shelter.species[name] = newShelterQueue()
+	return nil
+}
+
+func (shelter *Shelter) Species() []string {
+	names := make([]string, 0, len(shelter.species))
+	for name := range shelter.species {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (shelter *Shelter) SpeciesOf(animal IAnimal) (string, error) {
+	if name, isRegistered := shelter.speciesByType[reflect.TypeOf(animal)]; isRegistered {
+		return name, nil
+	}
+	return "", ErrUnknownSpecies
+}
+
+func (shelter *Shelter) Enqueue(animal IAnimal) error {
+	_, err := shelter.Admit(animal)
+	return err
+}
+
+func (shelter *Shelter) Admit(animal IAnimal) (ShelterEntry, error) {
+	if isNilAnimal(animal) {
+		return ShelterEntry{}, ErrNilAnimal
+	}
+
+	species, err := shelter.SpeciesOf(animal)
+	if err != nil {
+		return ShelterEntry{}, err
+	}
+
+	entry := ShelterEntry{
+		Animal:    animal,
+		Species:   species,
+		Sequence:  shelter.nextSequence,
+		ArrivedAt: shelter.now(),
+	}
+	if setter, ok := animal.(interface{ SetIntakeDate(time.Time) }); ok && animal.GetIntakeDate().IsZero() {
+		setter.SetIntakeDate(entry.ArrivedAt)
+	}
+	shelter.admit(entry)
+	shelter.nextSequence++
+	return entry, nil
+}
+
+func (shelter *Shelter) Peek() (IAnimal, error) {
+	record, hasRecord := shelter.arrivals.first()
+	if !hasRecord {
+		return nil, ErrShelterEmpty
+	}
+	return record.entry.Animal, nil
+}
+
+func (shelter *Shelter) DequeueAny() (IAnimal, error) {
+	adoption, err := shelter.AdoptAny()
+	return adoption.Animal, err
+}
+
+func (shelter *Shelter) AdoptAny() (AdoptionRecord, error) {
+	record, hasRecord := shelter.arrivals.first()
+	if !hasRecord {
+		return AdoptionRecord{}, ErrShelterEmpty
+	}
+	return shelter.adopt(record), nil
+}
+
+func (shelter *Shelter) DequeueSpecies(name string) (IAnimal, error) {
+	adoption, err := shelter.AdoptSpecies(name)
+	return adoption.Animal, err
+}
+
+func (shelter *Shelter) AdoptSpecies(name string) (AdoptionRecord, error) {
+	speciesQueue, isRegistered := shelter.species[name]
+	if !isRegistered {
+		return AdoptionRecord{}, ErrUnknownSpecies
+	}
+	record, hasRecord := speciesQueue.first()
+	if !hasRecord {
+		return AdoptionRecord{}, ErrShelterEmpty
+	}
+	return shelter.adopt(record), nil
+}
+
+func (shelter *Shelter) DequeueMatching(predicate func(IAnimal) bool) (IAnimal, error) {
+	if predicate == nil {
+		return nil, ErrNilPredicate
+	}
+
+	var matched *shelterRecord
+	shelter.arrivals.each(func(record *shelterRecord) bool {
+		if predicate(record.entry.Animal) {
+			matched = record
+			return false
+		}
+		return true
+	})
+
+	if matched == nil {
+		return nil, ErrNoMatchingAnimal
+	}
+	return shelter.adopt(matched).Animal, nil
+}
+
+func (shelter *Shelter) Len() int {
+	return shelter.arrivals.waiting
+}
+
+func (shelter *Shelter) CountSpecies(name string) int {
+	if speciesQueue, isRegistered := shelter.species[name]; isRegistered {
+		return speciesQueue.waiting
+	}
+	return 0
+}
+
+func (shelter *Shelter) Animals() []ShelterEntry {
+	entries := make([]ShelterEntry, 0, shelter.arrivals.waiting)
+	shelter.arrivals.each(func(record *shelterRecord) bool {
+		entries = append(entries, record.entry)
+		return true
+	})
+	return entries
+}
+
+func (shelter *Shelter) Adoptions(query AdoptionQuery) []AdoptionRecord {
+	records := []AdoptionRecord{}
+	for _, record := range shelter.adoptions {
+		if query.matches(record) {
+			records = append(records, record)
+		}
+	}
+	return records
+}
+
+func (shelter *Shelter) admit(entry ShelterEntry) {
+	record := &shelterRecord{
+		entry: entry,
+	}
+	shelter.arrivals.push(record)
+	shelter.species[entry.Species].push(record)
+}
+
+func (shelter *Shelter) adopt(record *shelterRecord) AdoptionRecord {
+	record.adopted = true
+	shelter.arrivals.remove()
+	shelter.species[record.entry.Species].remove()
+
+	adoption := AdoptionRecord{
+		ShelterEntry: record.entry,
+		AdoptedAt:    shelter.now(),
+	}
+	shelter.adoptions = append(shelter.adoptions, adoption)
+	return adoption
+}
+
+func isNilAnimal(animal IAnimal) bool {
+	if animal == nil {
+		return true
+	}
+	value := reflect.ValueOf(animal)
+	return value.Kind() == reflect.Pointer && value.IsNil()
+}
+
+type animalSnapshot struct {
+	Species   string
+	Sequence  uint64
+	ArrivedAt time.Time
+	AdoptedAt *time.Time `json:",omitempty"`
+	Animal    json.RawMessage
+}
+
+type shelterSnapshot struct {
+	NextSequence uint64
+	Animals      []animalSnapshot
+	Adoptions    []animalSnapshot
+}
+
+type animalCodec struct {
+	marshal   func(value any) ([]byte, error)
+	unmarshal func(data []byte, value any) error
+}
+
+var (
+	jsonAnimalCodec = animalCodec{
+		marshal:   json.Marshal,
+		unmarshal: json.Unmarshal,
+	}
+	binaryAnimalCodec = animalCodec{
+		marshal:   gobMarshal,
+		unmarshal: gobUnmarshal,
+	}
+)
+
+func gobMarshal(value any) ([]byte, error) {
+	var buffer bytes.Buffer
+	if err := gob.NewEncoder(&buffer).Encode(value); err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
+
+func gobUnmarshal(data []byte, value any) error {
+	return gob.NewDecoder(bytes.NewReader(data)).Decode(value)
+}
+
+func (shelter *Shelter) MarshalJSON() ([]byte, error) {
+	snapshot, err := shelter.snapshot(jsonAnimalCodec)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(snapshot)
+}
+
+func (shelter *Shelter) UnmarshalJSON(data []byte) error {
+	var snapshot shelterSnapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return err
+	}
+	return shelter.restore(snapshot, jsonAnimalCodec)
+}
+
+func (shelter *Shelter) MarshalBinary() ([]byte, error) {
+	snapshot, err := shelter.snapshot(binaryAnimalCodec)
+	if err != nil {
+		return nil, err
+	}
+	return gobMarshal(snapshot)
+}
+
+func (shelter *Shelter) UnmarshalBinary(data []byte) error {
+	var snapshot shelterSnapshot
+	if err := gobUnmarshal(data, &snapshot); err != nil {
+		return err
+	}
+	return shelter.restore(snapshot, binaryAnimalCodec)
+}
+
+func (shelter *Shelter) snapshot(codec animalCodec) (shelterSnapshot, error) {
+	snapshot := shelterSnapshot{
+		NextSequence: shelter.nextSequence,
+		Animals:      []animalSnapshot{},
+		Adoptions:    []animalSnapshot{},
+	}
+
+	for _, entry := range shelter.Animals() {
+		animal, err := encodeAnimal(entry, codec)
+		if err != nil {
+			return snapshot, err
+		}
+		snapshot.Animals = append(snapshot.Animals, animal)
+	}
+
+	for _, record := range shelter.adoptions {
+		animal, err := encodeAnimal(record.ShelterEntry, codec)
+		if err != nil {
+			return snapshot, err
+		}
+		adoptedAt := record.AdoptedAt
+		animal.AdoptedAt = &adoptedAt
+		snapshot.Adoptions = append(snapshot.Adoptions, animal)
+	}
+
+	return snapshot, nil
+}
+
+func (shelter *Shelter) restore(snapshot shelterSnapshot, codec animalCodec) error {
+	entries := make([]ShelterEntry, 0, len(snapshot.Animals))
+	for _, animal := range snapshot.Animals {
+		entry, err := shelter.decodeAnimal(animal, codec)
+		if err != nil {
+			return err
+		}
+		entries = append(entries, entry)
+	}
+
+	adoptions := make([]AdoptionRecord, 0, len(snapshot.Adoptions))
+	for _, animal := range snapshot.Adoptions {
+		if animal.AdoptedAt == nil {
+			return fmt.Errorf("Adoption of %s %d has no adoption time", animal.Species, animal.Sequence)
+		}
+		entry, err := shelter.decodeAnimal(animal, codec)
+		if err != nil {
+			return err
+		}
+		adoptions = append(adoptions, AdoptionRecord{
+			ShelterEntry: entry,
+			AdoptedAt:    *animal.AdoptedAt,
+		})
+	}
+
+	shelter.arrivals = newShelterQueue()
+	for name := range shelter.species {
+		shelter.species[name] = newShelterQueue()
+	}
+	for _, entry := range entries {
+		shelter.admit(entry)
+	}
+	shelter.adoptions = adoptions
+	shelter.nextSequence = snapshot.NextSequence
+
+	return nil
+}
+
+func encodeAnimal(entry ShelterEntry, codec animalCodec) (animalSnapshot, error) {
+	data, err := codec.marshal(entry.Animal)
+	if err != nil {
+		return animalSnapshot{}, err
+	}
+
+	return animalSnapshot{
+		Species:   entry.Species,
+		Sequence:  entry.Sequence,
+		ArrivedAt: entry.ArrivedAt,
+		Animal:    data,
+	}, nil
+}
+
+func (shelter *Shelter) decodeAnimal(snapshot animalSnapshot, codec animalCodec) (ShelterEntry, error) {
+	animal, err := shelter.newAnimal(snapshot.Species, snapshot.Animal, codec.unmarshal)
+	if err != nil {
+		return ShelterEntry{}, err
+	}
+
+	return ShelterEntry{
+		Animal:    animal,
+		Species:   snapshot.Species,
+		Sequence:  snapshot.Sequence,
+		ArrivedAt: snapshot.ArrivedAt,
+	}, nil
+}
+
+func (shelter *Shelter) newAnimal(species string, data []byte, unmarshal func([]byte, any) error) (IAnimal, error) {
+	animalType, isRegistered := shelter.typeBySpecies[species]
+	if !isRegistered {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownSpecies, species)
+	}
+
+	if animalType.Kind() == reflect.Pointer {
+		value := reflect.New(animalType.Elem())
+		if err := unmarshal(data, value.Interface()); err != nil {
+			return nil, err
+		}
+		return value.Interface().(IAnimal), nil
+	}
+
+	value := reflect.New(animalType)
+	if err := unmarshal(data, value.Interface()); err != nil {
+		return nil, err
+	}
+	return value.Elem().Interface().(IAnimal), nil
+}
+
+func DequeueSpecific[T IAnimal](shelter *Shelter) (T, error) {
+	var animal T
+	name, isRegistered := shelter.speciesByType[reflect.TypeOf((*T)(nil)).Elem()]
+	if !isRegistered {
+		return animal, ErrUnknownSpecies
+	}
+
+	adopted, err := shelter.DequeueSpecies(name)
+	if err != nil {
+		return animal, err
+	}
+	return adopted.(T), nil
+}
+
+const maxShelterRequestSize = 1 << 20
+
+// anySpecies is the path segment of POST /adopt/any, so no species may use it.
+const anySpecies = "any"
+
+type ShelterHandler struct {
+	shelter *SyncShelter
+}
+
+type shelterAnimalResponse struct {
+	Species   string     `json:"species"`
+	Name      string     `json:"name"`
+	Sequence  uint64     `json:"sequence"`
+	ArrivedAt time.Time  `json:"arrivedAt"`
+	AdoptedAt *time.Time `json:"adoptedAt,omitempty"`
+	Animal    IAnimal    `json:"animal"`
+}
+
+type shelterErrorResponse struct {
+	Error string `json:"error"`
+}
+
+func newShelterHandler(shelter *SyncShelter) *ShelterHandler {
+	return &ShelterHandler{
+		shelter: shelter,
+	}
+}
+
+func (handler *ShelterHandler) ServeHTTP(writer http.ResponseWriter, request *http.Request) {
+	path := strings.Trim(request.URL.Path, "/")
+	segments := strings.Split(path, "/")
+
+	switch {
+	case path == "animals":
+		switch request.Method {
+		case http.MethodGet:
+			handler.listAnimals(writer)
+		case http.MethodPost:
+			handler.admitAnimal(writer, request)
+		default:
+			writeMethodNotAllowed(writer, http.MethodGet, http.MethodPost)
+		}
+	case len(segments) == 2 && segments[0] == "adopt" && segments[1] != "":
+		if request.Method != http.MethodPost {
+			writeMethodNotAllowed(writer, http.MethodPost)
+			return
+		}
+		handler.adoptAnimal(writer, segments[1])
+	default:
+		writeShelterError(writer, http.StatusNotFound, errors.New("Route not found"))
+	}
+}
+
+func (handler *ShelterHandler) listAnimals(writer http.ResponseWriter) {
+	entries := handler.shelter.Animals()
+	animals := make([]shelterAnimalResponse, 0, len(entries))
+	for _, entry := range entries {
+		animals = append(animals, newShelterAnimalResponse(entry))
+	}
+	writeShelterJSON(writer, http.StatusOK, animals)
+}
+
+func (handler *ShelterHandler) admitAnimal(writer http.ResponseWriter, request *http.Request) {
+	body, err := io.ReadAll(http.MaxBytesReader(writer, request.Body, maxShelterRequestSize))
+	if err != nil {
+		writeShelterError(writer, http.StatusBadRequest, err)
+		return
+	}
+
+	var header struct {
+		Species string `json:"species"`
+	}
+	if err := json.Unmarshal(body, &header); err != nil {
+		writeShelterError(writer, http.StatusBadRequest, err)
+		return
+	}
+
+	entry, err := handler.shelter.AdmitJSON(header.Species, body)
+	if err != nil {
+		writeShelterError(writer, http.StatusBadRequest, err)
+		return
+	}
+	writeShelterJSON(writer, http.StatusCreated, newShelterAnimalResponse(entry))
+}
+
+func (handler *ShelterHandler) adoptAnimal(writer http.ResponseWriter, species string) {
+	var adoption AdoptionRecord
+	var err error
+	if species == anySpecies {
+		adoption, err = handler.shelter.AdoptAny()
+	} else {
+		adoption, err = handler.shelter.AdoptSpecies(species)
+	}
+
+	switch {
+	case errors.Is(err, ErrUnknownSpecies):
+		writeShelterError(writer, http.StatusNotFound, err)
+	case errors.Is(err, ErrShelterEmpty):
+		writeShelterError(writer, http.StatusConflict, err)
+	case err != nil:
+		writeShelterError(writer, http.StatusInternalServerError, err)
+	default:
+		response := newShelterAnimalResponse(adoption.ShelterEntry)
+		response.AdoptedAt = &adoption.AdoptedAt
+		writeShelterJSON(writer, http.StatusOK, response)
+	}
+}
+
+func newShelterAnimalResponse(entry ShelterEntry) shelterAnimalResponse {
+	return shelterAnimalResponse{
+		Species:   entry.Species,
+		Name:      entry.Animal.GetName(),
+		Sequence:  entry.Sequence,
+		ArrivedAt: entry.ArrivedAt,
+		Animal:    entry.Animal,
+	}
+}
+
+func writeMethodNotAllowed(writer http.ResponseWriter, allowed ...string) {
+	writer.Header().Set("Allow", strings.Join(allowed, ", "))
+	writeShelterError(writer, http.StatusMethodNotAllowed, errors.New("Method not allowed"))
+}
+
+func writeShelterError(writer http.ResponseWriter, status int, err error) {
+	writeShelterJSON(writer, status, shelterErrorResponse{Error: err.Error()})
+}
+
+func writeShelterJSON(writer http.ResponseWriter, status int, value any) {
+	writer.Header().Set("Content-Type", "application/json")
+	writer.WriteHeader(status)
+	_ = json.NewEncoder(writer).Encode(value)
+}
+
+type SyncStack[T any] struct {
+	mutex sync.Mutex
+	stack StackInterface[T]
+}
+
+func newSyncStack[T any](stack StackInterface[T]) *SyncStack[T] {
+	return &SyncStack[T]{
+		stack: stack,
+	}
+}
+
+func (stack *SyncStack[T]) Push(value T) {
+	stack.mutex.Lock()
+	defer stack.mutex.Unlock()
+	stack.stack.Push(value)
+}
+
+func (stack *SyncStack[T]) Peek() (T, bool) {
+	stack.mutex.Lock()
+	defer stack.mutex.Unlock()
+	return stack.stack.Peek()
+}
+
+func (stack *SyncStack[T]) TryPop() (T, bool) {
+	stack.mutex.Lock()
+	defer stack.mutex.Unlock()
+	return stack.stack.TryPop()
+}
+
+func (stack *SyncStack[T]) IsEmpty() bool {
+	stack.mutex.Lock()
+	defer stack.mutex.Unlock()
+	return stack.stack.IsEmpty()
+}
+
+type SyncQueue[T any] struct {
+	mutex sync.Mutex
+	queue QueueInterface[T]
+}
+
+func newSyncQueue[T any](queue QueueInterface[T]) *SyncQueue[T] {
+	return &SyncQueue[T]{
+		queue: queue,
+	}
+}
+
+func (queue *SyncQueue[T]) Enqueue(value T) {
+	queue.mutex.Lock()
+	defer queue.mutex.Unlock()
+	queue.queue.Enqueue(value)
+}
+
+func (queue *SyncQueue[T]) Peek() (T, bool) {
+	queue.mutex.Lock()
+	defer queue.mutex.Unlock()
+	return queue.queue.Peek()
+}
+
+func (queue *SyncQueue[T]) TryDequeue() (T, bool) {
+	queue.mutex.Lock()
+	defer queue.mutex.Unlock()
+	return queue.queue.TryDequeue()
+}
+
+func (queue *SyncQueue[T]) IsEmpty() bool {
+	queue.mutex.Lock()
+	defer queue.mutex.Unlock()
+	return queue.queue.IsEmpty()
+}
+
+type SyncMinStack[T constraints.Ordered] struct {
+	mutex sync.Mutex
+	stack *MinStack[T]
+}
+
+func newSyncMinStack[T constraints.Ordered]() *SyncMinStack[T] {
+	return &SyncMinStack[T]{
+		stack: newMinStack[T](),
+	}
+}
+
+func (stack *SyncMinStack[T]) Push(value T) {
+	stack.mutex.Lock()
+	defer stack.mutex.Unlock()
+	stack.stack.Push(value)
+}
+
+func (stack *SyncMinStack[T]) Peek() (T, bool) {
+	stack.mutex.Lock()
+	defer stack.mutex.Unlock()
+	return stack.stack.Peek()
+}
+
+func (stack *SyncMinStack[T]) TryPop() (T, bool) {
+	stack.mutex.Lock()
+	defer stack.mutex.Unlock()
+	return stack.stack.TryPop()
+}
+
+func (stack *SyncMinStack[T]) TryMin() (T, bool) {
+	stack.mutex.Lock()
+	defer stack.mutex.Unlock()
+	return stack.stack.TryMin()
+}
+
+type SyncShelter struct {
+	mutex   sync.Mutex
+	shelter *Shelter
+}
+
+func newSyncShelter() *SyncShelter {
+	return &SyncShelter{
+		shelter: newShelter(),
+	}
+}
+
+func (shelter *SyncShelter) RegisterSpecies(name string, prototype IAnimal) error {
+	shelter.mutex.Lock()
+	defer shelter.mutex.Unlock()
+	return shelter.shelter.RegisterSpecies(name, prototype)
+}
+
+func (shelter *SyncShelter) Peek() (IAnimal, error) {
+	shelter.mutex.Lock()
+	defer shelter.mutex.Unlock()
+	return shelter.shelter.Peek()
+}
+
+func (shelter *SyncShelter) Enqueue(value IAnimal) error {
+	shelter.mutex.Lock()
+	defer shelter.mutex.Unlock()
+	return shelter.shelter.Enqueue(value)
+}
+
+func (shelter *SyncShelter) DequeueAny() (IAnimal, error) {
+	shelter.mutex.Lock()
+	defer shelter.mutex.Unlock()
+	return shelter.shelter.DequeueAny()
+}
+
+func (shelter *SyncShelter) DequeueSpecies(name string) (IAnimal, error) {
+	shelter.mutex.Lock()
+	defer shelter.mutex.Unlock()
+	return shelter.shelter.DequeueSpecies(name)
+}
+
+func (shelter *SyncShelter) Admit(animal IAnimal) (ShelterEntry, error) {
+	shelter.mutex.Lock()
+	defer shelter.mutex.Unlock()
+	return shelter.shelter.Admit(animal)
+}
+
+func (shelter *SyncShelter) AdmitJSON(species string, data []byte) (ShelterEntry, error) {
+	shelter.mutex.Lock()
+	defer shelter.mutex.Unlock()
+
+	animal, err := shelter.shelter.newAnimal(species, data, json.Unmarshal)
+	if err != nil {
+		return ShelterEntry{}, err
+	}
+	return shelter.shelter.Admit(animal)
+}
+
+func (shelter *SyncShelter) AdoptAny() (AdoptionRecord, error) {
+	shelter.mutex.Lock()
+	defer shelter.mutex.Unlock()
+	return shelter.shelter.AdoptAny()
+}
+
+func (shelter *SyncShelter) AdoptSpecies(name string) (AdoptionRecord, error) {
+	shelter.mutex.Lock()
+	defer shelter.mutex.Unlock()
+	return shelter.shelter.AdoptSpecies(name)
+}
+
+func (shelter *SyncShelter) Animals() []ShelterEntry {
+	shelter.mutex.Lock()
+	defer shelter.mutex.Unlock()
+	return shelter.shelter.Animals()
+}
+
+func (shelter *SyncShelter) DequeueMatching(predicate func(IAnimal) bool) (IAnimal, error) {
+	shelter.mutex.Lock()
+	defer shelter.mutex.Unlock()
+	return shelter.shelter.DequeueMatching(predicate)
+}
+
+type TreiberStack[T any] struct {
+	top unsafe.Pointer
+}
+
+func newTreiberStack[T any]() *TreiberStack[T] {
+	return &TreiberStack[T]{}
+}
+
+func (stack *TreiberStack[T]) Push(value T) {
+	node := &Node[T]{
+		value: value,
+	}
+
+	for {
+		top := atomic.LoadPointer(&stack.top)
+		node.next = (*Node[T])(top)
+		if atomic.CompareAndSwapPointer(&stack.top, top, unsafe.Pointer(node)) {
+			return
+		}
+	}
+}
+
+func (stack *TreiberStack[T]) Peek() (T, bool) {
+	top := (*Node[T])(atomic.LoadPointer(&stack.top))
+	if top != nil {
+		return top.value, true
+	}
+	var zero T
+	return zero, false
+}
+
+func (stack *TreiberStack[T]) TryPop() (T, bool) {
+	for {
+		top := atomic.LoadPointer(&stack.top)
+		if top == nil {
+			var zero T
+			return zero, false
+		}
+
+		topNode := (*Node[T])(top)
+		if atomic.CompareAndSwapPointer(&stack.top, top, unsafe.Pointer(topNode.next)) {
+			return topNode.value, true
+		}
+	}
+}
+
+func (stack *TreiberStack[T]) IsEmpty() bool {
+	return atomic.LoadPointer(&stack.top) == nil
+}
+
+type lockFreeNode[T any] struct {
+	value T
+	next  unsafe.Pointer
+}
+
+type MichaelScottQueue[T any] struct {
+	head unsafe.Pointer
+	tail unsafe.Pointer
+}
+
+func newMichaelScottQueue[T any]() *MichaelScottQueue[T] {
+	sentinel := unsafe.Pointer(&lockFreeNode[T]{})
+	return &MichaelScottQueue[T]{
+		head: sentinel,
+		tail: sentinel,
+	}
+}
+
+func (queue *MichaelScottQueue[T]) Enqueue(value T) {
+	node := unsafe.Pointer(&lockFreeNode[T]{
+		value: value,
+	})
+
+	for {
+		tail := atomic.LoadPointer(&queue.tail)
+		next := atomic.LoadPointer(&(*lockFreeNode[T])(tail).next)
+		if tail != atomic.LoadPointer(&queue.tail) {
+			continue
+		}
+
+		if next != nil {
+			atomic.CompareAndSwapPointer(&queue.tail, tail, next)
+			continue
+		}
+
+		if atomic.CompareAndSwapPointer(&(*lockFreeNode[T])(tail).next, nil, node) {
+			atomic.CompareAndSwapPointer(&queue.tail, tail, node)
+			return
+		}
+	}
+}
+
+func (queue *MichaelScottQueue[T]) Peek() (T, bool) {
+	head := atomic.LoadPointer(&queue.head)
+	next := atomic.LoadPointer(&(*lockFreeNode[T])(head).next)
+	if next != nil {
+		return (*lockFreeNode[T])(next).value, true
+	}
+	var zero T
+	return zero, false
+}
+
+func (queue *MichaelScottQueue[T]) TryDequeue() (T, bool) {
+	for {
+		head := atomic.LoadPointer(&queue.head)
+		tail := atomic.LoadPointer(&queue.tail)
+		next := atomic.LoadPointer(&(*lockFreeNode[T])(head).next)
+		if head != atomic.LoadPointer(&queue.head) {
+			continue
+		}
+
+		if next == nil {
+			var zero T
+			return zero, false
+		}
+
+		if head == tail {
+			atomic.CompareAndSwapPointer(&queue.tail, tail, next)
+			continue
+		}
+
+		firstValue := (*lockFreeNode[T])(next).value
+		if atomic.CompareAndSwapPointer(&queue.head, head, next) {
+			return firstValue, true
+		}
+	}
+}
+
+func (queue *MichaelScottQueue[T]) IsEmpty() bool {
+	head := atomic.LoadPointer(&queue.head)
+	return atomic.LoadPointer(&(*lockFreeNode[T])(head).next) == nil
+}
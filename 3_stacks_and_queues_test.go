@@ -1,8 +1,19 @@
 package main
 
 import (
+	"context"
+	"encoding/json"
+	"fmt"
 	"github.com/stretchr/testify/assert"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 )
 
 func TestStack(t *testing.T) {
@@ -33,282 +44,2795 @@ func TestStack(t *testing.T) {
 	})
 }
 
-func TestQueue(t *testing.T) {
-	t.Run("Pick returns nil given empty queue", func(t *testing.T) {
-		queue := newQueue[int]()
-		assert.Nil(t, queue.Pick())
+func TestStackValueAPI(t *testing.T) {
+	t.Run("Peek and TryPop report empty stack", func(t *testing.T) {
+		stack := newStack[int]()
+		_, ok := stack.Peek()
+		assert.False(t, ok)
+		_, ok = stack.TryPop()
+		assert.False(t, ok)
+		assert.Equal(t, 0, stack.Len())
 	})
 
-	t.Run("Pick returns first value given queue with value", func(t *testing.T) {
-		queue := newQueue[int]()
-		queue.Enqueue(44)
-		assert.Equal(t, 44, *queue.Pick())
+	t.Run("TryPop returns values in LIFO order", func(t *testing.T) {
+		stack := newStack[int]()
+		stack.Push(44)
+		stack.Push(12)
+		assert.Equal(t, 2, stack.Len())
+
+		value, ok := stack.Peek()
+		assert.True(t, ok)
+		assert.Equal(t, 12, value)
+
+		value, _ = stack.TryPop()
+		assert.Equal(t, 12, value)
+		value, _ = stack.TryPop()
+		assert.Equal(t, 44, value)
+		assert.Equal(t, 0, stack.Len())
 	})
 
-	t.Run("Dequeue returns first value given queue with value", func(t *testing.T) {
-		queue := newQueue[int]()
-		queue.Enqueue(44)
-		assert.Equal(t, 44, *queue.Dequeue())
+	t.Run("Clear removes all values", func(t *testing.T) {
+		stack := newStack[int]()
+		stack.Push(44)
+		stack.Push(12)
+		stack.Clear()
+		assert.True(t, stack.IsEmpty())
+		assert.Equal(t, 0, stack.Len())
 	})
 
-	t.Run("Dequeue returns first value given queue with multiple values", func(t *testing.T) {
-		queue := newQueue[int]()
-		queue.Enqueue(44)
-		queue.Enqueue(12)
-		queue.Enqueue(14)
-		assert.Equal(t, 44, *queue.Dequeue())
-		assert.Equal(t, 12, *queue.Dequeue())
-		assert.Equal(t, 14, *queue.Dequeue())
-		assert.Nil(t, queue.Dequeue())
+	t.Run("Pick does not expose internal state", func(t *testing.T) {
+		stack := newStack[int]()
+		stack.Push(44)
+		*stack.Pick() = 12
+		assert.Equal(t, 44, *stack.Pop())
 	})
 }
 
-func TestMinStack(t *testing.T) {
-	t.Run("Return nil for min given empty stack", func(t *testing.T) {
-		stack := newMinStack[int]()
-		assert.Nil(t, stack.Min())
+func TestContainersOfAnyType(t *testing.T) {
+	type point struct {
+		x, y int
+	}
+
+	t.Run("Stack stores structs", func(t *testing.T) {
+		stack := newStack[point]()
+		stack.Push(point{x: 1, y: 2})
+		value, _ := stack.TryPop()
+		assert.Equal(t, point{x: 1, y: 2}, value)
 	})
 
-	t.Run("Min returns minimum value given stack with multiple values", func(t *testing.T) {
-		stack := newMinStack[int]()
-		stack.Push(12)
-		stack.Push(44)
-		assert.Equal(t, 12, *stack.Min())
+	t.Run("Queue stores pointers", func(t *testing.T) {
+		first := &point{x: 1}
+		queue := newQueue[*point]()
+		queue.Enqueue(first)
+		queue.Enqueue(nil)
+		value, _ := queue.TryDequeue()
+		assert.Same(t, first, value)
+		value, ok := queue.TryDequeue()
+		assert.True(t, ok)
+		assert.Nil(t, value)
 	})
 
-	t.Run("Min returns minimum value given after poping up minimum value", func(t *testing.T) {
-		stack := newMinStack[int]()
-		stack.Push(15)
-		stack.Push(44)
-		stack.Push(12)
-		_ = stack.Pop()
-		assert.Equal(t, 15, *stack.Min())
+	t.Run("QueueOnTwoStacks stores interfaces", func(t *testing.T) {
+		queue := newQueueOnTwoStacks[IAnimal]()
+		queue.Enqueue(newCat("Cat1"))
+		queue.Enqueue(newDog("Dog1"))
+		value, _ := queue.TryDequeue()
+		assert.Equal(t, "Cat1", value.GetName())
 	})
 }
 
-func TestHanoiGame(t *testing.T) {
+func TestSetOfStacks(t *testing.T) {
+	t.Run("Error when creating set without capacity", func(t *testing.T) {
+		set, err := newSetOfStacks[int](0)
+		assert.Nil(t, set)
+		assert.NotNil(t, err)
+	})
 
-	t.Run("Error when creating 0 elements tower", func(t *testing.T) {
-		game, err := newHanoiGame(0)
-		assert.Nil(t, game)
+	t.Run("TryPop reports empty set", func(t *testing.T) {
+		set, _ := newSetOfStacks[int](2)
+		_, ok := set.TryPop()
+		assert.False(t, ok)
+		_, ok = set.Peek()
+		assert.False(t, ok)
+		assert.True(t, set.IsEmpty())
+	})
+
+	t.Run("Push rolls over to new stack given full stack", func(t *testing.T) {
+		set, _ := newSetOfStacks[int](2)
+		for value := 1; value <= 5; value++ {
+			set.Push(value)
+		}
+
+		assert.Equal(t, 3, set.StackCount())
+		assert.Equal(t, 5, set.Len())
+
+		for value := 5; value >= 1; value-- {
+			popped, ok := set.TryPop()
+			assert.True(t, ok)
+			assert.Equal(t, value, popped)
+		}
+		assert.Equal(t, 0, set.StackCount())
+	})
+
+	t.Run("PopAt rebalances following stacks", func(t *testing.T) {
+		set, _ := newSetOfStacks[int](3)
+		for value := 1; value <= 8; value++ {
+			set.Push(value)
+		}
+
+		value, ok := set.PopAt(0)
+		assert.True(t, ok)
+		assert.Equal(t, 3, value)
+		assert.Equal(t, 3, set.StackCount())
+
+		value, _ = set.PopAt(1)
+		assert.Equal(t, 7, value)
+		assert.Equal(t, 2, set.StackCount())
+
+		expected := []int{8, 6, 5, 4, 2, 1}
+		for _, expectedValue := range expected {
+			value, _ = set.TryPop()
+			assert.Equal(t, expectedValue, value)
+		}
+		assert.True(t, set.IsEmpty())
+	})
+
+	t.Run("PopAt reports index out of range", func(t *testing.T) {
+		set, _ := newSetOfStacks[int](3)
+		set.Push(1)
+		_, ok := set.PopAt(1)
+		assert.False(t, ok)
+		_, ok = set.PopAt(-1)
+		assert.False(t, ok)
+	})
+}
+
+func TestSortStack(t *testing.T) {
+	t.Run("Empty stack stays empty", func(t *testing.T) {
+		stack := newStack[int]()
+		SortStack(stack)
+		assert.True(t, stack.IsEmpty())
+	})
+
+	t.Run("Smallest value ends on top", func(t *testing.T) {
+		stack := newStack[int]()
+		for _, value := range []int{44, 15, 108, 55, 12, 15} {
+			stack.Push(value)
+		}
+
+		SortStack(stack)
+
+		for _, expected := range []int{12, 15, 15, 44, 55, 108} {
+			value, _ := stack.TryPop()
+			assert.Equal(t, expected, value)
+		}
+		assert.True(t, stack.IsEmpty())
+	})
+}
+
+func TestSort(t *testing.T) {
+	t.Run("Sorts slice stack with custom comparator", func(t *testing.T) {
+		stack := newSliceStack[string]()
+		for _, value := range []string{"ccc", "a", "bb", "dddd"} {
+			stack.Push(value)
+		}
+
+		Sort[string](stack, func(a, b string) bool {
+			return len(a) > len(b)
+		})
+
+		for _, expected := range []string{"dddd", "ccc", "bb", "a"} {
+			value, _ := stack.TryPop()
+			assert.Equal(t, expected, value)
+		}
+	})
+
+	stacks := map[string]func() StackInterface[int]{
+		"DequeStack":   func() StackInterface[int] { return newDequeStack[int]() },
+		"SyncStack":    func() StackInterface[int] { return newSyncStack[int](newStack[int]()) },
+		"TreiberStack": func() StackInterface[int] { return newTreiberStack[int]() },
+	}
+
+	for name, createStack := range stacks {
+		createStack := createStack
+
+		t.Run("Sorts "+name, func(t *testing.T) {
+			stack := createStack()
+			for value := 0; value < 100; value++ {
+				stack.Push((value * 37) % 100)
+			}
+
+			Sort(stack, func(a, b int) bool {
+				return a < b
+			})
+
+			for expected := 0; expected < 100; expected++ {
+				value, ok := stack.TryPop()
+				assert.True(t, ok)
+				assert.Equal(t, expected, value)
+			}
+			assert.True(t, stack.IsEmpty())
+		})
+	}
+}
+
+func TestMultiStack(t *testing.T) {
+	t.Run("Error when creating without stacks", func(t *testing.T) {
+		multiStack, err := newMultiStack[int](0, 3)
+		assert.Nil(t, multiStack)
 		assert.NotNil(t, err)
 	})
 
-	t.Run("Check initial conditions", func(t *testing.T) {
-		game, err := newHanoiGame(5)
-		assert.Nil(t, err)
-		assert.Equal(t, 1, *game.sourceRod.Pop())
-		assert.Equal(t, 2, *game.sourceRod.Pop())
-		assert.Equal(t, 3, *game.sourceRod.Pop())
-		assert.Equal(t, 4, *game.sourceRod.Pop())
-		assert.Equal(t, 5, *game.sourceRod.Pop())
-		assert.True(t, game.spareRod.IsEmpty())
-		assert.True(t, game.destinationRod.IsEmpty())
+	t.Run("Peek and TryPop report empty stack", func(t *testing.T) {
+		multiStack, _ := newMultiStack[int](3, 2)
+		_, ok := multiStack.Peek(1)
+		assert.False(t, ok)
+		_, ok = multiStack.TryPop(1)
+		assert.False(t, ok)
+		assert.True(t, multiStack.IsEmpty(1))
 	})
 
-	t.Run("Solves game with 1 disk", func(t *testing.T) {
-		game, _ := newHanoiGame(1)
-		game.Solve()
+	t.Run("Error given stack index out of range", func(t *testing.T) {
+		multiStack, _ := newMultiStack[int](3, 2)
+		assert.NotNil(t, multiStack.Push(3, 1))
+		assert.NotNil(t, multiStack.Push(-1, 1))
+		_, ok := multiStack.TryPop(3)
+		assert.False(t, ok)
+	})
 
-		assert.True(t, game.sourceRod.IsEmpty())
-		assert.True(t, game.spareRod.IsEmpty())
-		assert.Equal(t, 1, *game.destinationRod.Pop())
+	t.Run("Stacks keep separate LIFO order", func(t *testing.T) {
+		multiStack, _ := newMultiStack[int](3, 2)
+		_ = multiStack.Push(0, 1)
+		_ = multiStack.Push(1, 10)
+		_ = multiStack.Push(0, 2)
+		_ = multiStack.Push(2, 100)
+
+		value, _ := multiStack.Peek(0)
+		assert.Equal(t, 2, value)
+		value, _ = multiStack.TryPop(0)
+		assert.Equal(t, 2, value)
+		value, _ = multiStack.TryPop(1)
+		assert.Equal(t, 10, value)
+		value, _ = multiStack.TryPop(2)
+		assert.Equal(t, 100, value)
+		value, _ = multiStack.TryPop(0)
+		assert.Equal(t, 1, value)
 	})
 
-	t.Run("Solves game with 2 disk", func(t *testing.T) {
-		game, _ := newHanoiGame(2)
-		game.Solve()
+	t.Run("Full stack borrows space from neighbours", func(t *testing.T) {
+		multiStack, _ := newMultiStack[int](3, 2)
+		_ = multiStack.Push(1, 10)
+		_ = multiStack.Push(2, 100)
+		for value := 1; value <= 4; value++ {
+			assert.Nil(t, multiStack.Push(0, value))
+		}
 
-		assert.True(t, game.sourceRod.IsEmpty())
-		assert.True(t, game.spareRod.IsEmpty())
-		assert.Equal(t, 1, *game.destinationRod.Pop())
-		assert.Equal(t, 2, *game.destinationRod.Pop())
+		assert.Equal(t, ErrMultiStackFull, multiStack.Push(2, 101))
+		assert.Equal(t, 4, multiStack.Len(0))
+
+		for value := 4; value >= 1; value-- {
+			popped, _ := multiStack.TryPop(0)
+			assert.Equal(t, value, popped)
+		}
+		value, _ := multiStack.TryPop(1)
+		assert.Equal(t, 10, value)
+		value, _ = multiStack.TryPop(2)
+		assert.Equal(t, 100, value)
 	})
 
-	t.Run("Solves game with 3 disk", func(t *testing.T) {
-		game, _ := newHanoiGame(3)
-		game.Solve()
+	t.Run("Last stack wraps around backing array", func(t *testing.T) {
+		multiStack, _ := newMultiStack[int](3, 2)
+		_ = multiStack.Push(0, 1)
+		for value := 1; value <= 5; value++ {
+			assert.Nil(t, multiStack.Push(2, 100+value))
+		}
 
-		assert.True(t, game.sourceRod.IsEmpty())
-		assert.True(t, game.spareRod.IsEmpty())
-		assert.Equal(t, 1, *game.destinationRod.Pop())
-		assert.Equal(t, 2, *game.destinationRod.Pop())
-		assert.Equal(t, 3, *game.destinationRod.Pop())
+		for value := 5; value >= 1; value-- {
+			popped, _ := multiStack.TryPop(2)
+			assert.Equal(t, 100+value, popped)
+		}
+		value, _ := multiStack.TryPop(0)
+		assert.Equal(t, 1, value)
 	})
 
-	t.Run("Solves game with 3 disk", func(t *testing.T) {
-		game, _ := newHanoiGame(6)
-		game.Solve()
+	t.Run("Matches separate stacks given mixed operations", func(t *testing.T) {
+		multiStack, _ := newMultiStack[int](4, 3)
+		expected := make([][]int, 4)
+		seed := 11
 
-		assert.True(t, game.sourceRod.IsEmpty())
-		assert.True(t, game.spareRod.IsEmpty())
-		assert.Equal(t, 1, *game.destinationRod.Pop())
-		assert.Equal(t, 2, *game.destinationRod.Pop())
-		assert.Equal(t, 3, *game.destinationRod.Pop())
-		assert.Equal(t, 4, *game.destinationRod.Pop())
-		assert.Equal(t, 5, *game.destinationRod.Pop())
-		assert.Equal(t, 6, *game.destinationRod.Pop())
+		for step := 0; step < 5000; step++ {
+			seed = (seed*1103515245 + 12345) % 2147483648
+			stackIndex := (seed / 7) % 4
+			if seed%3 != 0 {
+				err := multiStack.Push(stackIndex, step)
+				total := 0
+				for _, values := range expected {
+					total += len(values)
+				}
+				if total == 12 {
+					assert.Equal(t, ErrMultiStackFull, err)
+				} else {
+					assert.Nil(t, err)
+					expected[stackIndex] = append(expected[stackIndex], step)
+				}
+			} else {
+				value, ok := multiStack.TryPop(stackIndex)
+				values := expected[stackIndex]
+				assert.Equal(t, len(values) != 0, ok)
+				if ok {
+					assert.Equal(t, values[len(values)-1], value)
+					expected[stackIndex] = values[:len(values)-1]
+				}
+			}
+		}
 	})
 }
 
-func TestQueueOnTwoStacks(t *testing.T) {
+func TestSliceStack(t *testing.T) {
+	t.Run("Peek reports no value given empty stack", func(t *testing.T) {
+		stack := newSliceStack[int]()
+		_, ok := stack.Peek()
+		assert.False(t, ok)
+		assert.True(t, stack.IsEmpty())
+	})
+
+	t.Run("Peek returns last value given stack with value", func(t *testing.T) {
+		stack := newSliceStack[int]()
+		stack.Push(44)
+		value, _ := stack.Peek()
+		assert.Equal(t, 44, value)
+		assert.False(t, stack.IsEmpty())
+	})
+
+	t.Run("TryPop returns last value given stack with multiple values", func(t *testing.T) {
+		stack := newSliceStack[int]()
+		stack.Push(44)
+		stack.Push(12)
+		assert.Equal(t, 2, stack.Len())
+		value, _ := stack.TryPop()
+		assert.Equal(t, 12, value)
+		value, _ = stack.TryPop()
+		assert.Equal(t, 44, value)
+		_, ok := stack.TryPop()
+		assert.False(t, ok)
+		assert.Equal(t, 0, stack.Len())
+	})
+
+	t.Run("Reserve grows capacity and keeps values", func(t *testing.T) {
+		stack := newSliceStack[int]()
+		stack.Push(1)
+		stack.Push(2)
+		stack.Reserve(100)
+		assert.Equal(t, 100, stack.Cap())
+		value, _ := stack.TryPop()
+		assert.Equal(t, 2, value)
+		value, _ = stack.TryPop()
+		assert.Equal(t, 1, value)
+	})
+
+	t.Run("Reserve does not shrink capacity", func(t *testing.T) {
+		stack := newSliceStackWithCapacity[int](50)
+		stack.Reserve(10)
+		assert.Equal(t, 50, stack.Cap())
+	})
+
+	t.Run("Shrink releases unused capacity", func(t *testing.T) {
+		stack := newSliceStackWithCapacity[int](50)
+		stack.Push(1)
+		stack.Push(2)
+		stack.Shrink()
+		assert.Equal(t, 2, stack.Cap())
+		value, _ := stack.TryPop()
+		assert.Equal(t, 2, value)
+		value, _ = stack.TryPop()
+		assert.Equal(t, 1, value)
+	})
+
+	t.Run("Peek and TryPop return values", func(t *testing.T) {
+		stack := newSliceStack[int]()
+		_, ok := stack.Peek()
+		assert.False(t, ok)
+
+		stack.Push(44)
+		value, ok := stack.Peek()
+		assert.True(t, ok)
+		assert.Equal(t, 44, value)
+
+		value, ok = stack.TryPop()
+		assert.True(t, ok)
+		assert.Equal(t, 44, value)
+		_, ok = stack.TryPop()
+		assert.False(t, ok)
+	})
+}
+
+func BenchmarkStackPushPop(b *testing.B) {
+	b.Run("Stack", func(b *testing.B) {
+		benchmarkStackPushPop(b, newStack[int]())
+	})
+
+	b.Run("SliceStack", func(b *testing.B) {
+		benchmarkStackPushPop(b, newSliceStack[int]())
+	})
+
+	b.Run("DequeStack", func(b *testing.B) {
+		benchmarkStackPushPop(b, newDequeStack[int]())
+	})
+}
+
+func benchmarkStackPushPop(b *testing.B, stack StackInterface[int]) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		for value := 0; value < 1000; value++ {
+			stack.Push(value)
+		}
+		for !stack.IsEmpty() {
+			_, _ = stack.TryPop()
+		}
+	}
+}
+
+func TestQueue(t *testing.T) {
 	t.Run("Pick returns nil given empty queue", func(t *testing.T) {
-		queue := newQueueOnTwoStacks[int]()
+		queue := newQueue[int]()
 		assert.Nil(t, queue.Pick())
 	})
 
 	t.Run("Pick returns first value given queue with value", func(t *testing.T) {
-		queue := newQueueOnTwoStacks[int]()
+		queue := newQueue[int]()
 		queue.Enqueue(44)
 		assert.Equal(t, 44, *queue.Pick())
 	})
 
 	t.Run("Dequeue returns first value given queue with value", func(t *testing.T) {
-		queue := newQueueOnTwoStacks[int]()
+		queue := newQueue[int]()
 		queue.Enqueue(44)
 		assert.Equal(t, 44, *queue.Dequeue())
 	})
 
 	t.Run("Dequeue returns first value given queue with multiple values", func(t *testing.T) {
-		queue := newQueueOnTwoStacks[int]()
+		queue := newQueue[int]()
 		queue.Enqueue(44)
 		queue.Enqueue(12)
+		queue.Enqueue(14)
 		assert.Equal(t, 44, *queue.Dequeue())
 		assert.Equal(t, 12, *queue.Dequeue())
+		assert.Equal(t, 14, *queue.Dequeue())
 		assert.Nil(t, queue.Dequeue())
 	})
 }
 
-func TestMaxStack(t *testing.T) {
-	t.Run("Pick returns nil given empty stack", func(t *testing.T) {
-		stack := newMaxStack[int]()
-		assert.Nil(t, stack.Pick())
+func TestQueueValueAPI(t *testing.T) {
+	t.Run("Peek and TryDequeue report empty queue", func(t *testing.T) {
+		queue := newQueue[int]()
+		_, ok := queue.Peek()
+		assert.False(t, ok)
+		_, ok = queue.TryDequeue()
+		assert.False(t, ok)
 	})
 
-	t.Run("Pick returns last value given stack with value", func(t *testing.T) {
-		stack := newMaxStack[int]()
-		stack.Push(44)
-		assert.Equal(t, 44, *stack.Pick())
+	t.Run("TryDequeue returns values in FIFO order", func(t *testing.T) {
+		queue := newQueue[int]()
+		queue.Enqueue(44)
+		queue.Enqueue(12)
+		assert.Equal(t, 2, queue.Len())
+
+		value, _ := queue.Peek()
+		assert.Equal(t, 44, value)
+		value, _ = queue.TryDequeue()
+		assert.Equal(t, 44, value)
+		value, _ = queue.TryDequeue()
+		assert.Equal(t, 12, value)
+		assert.Equal(t, 0, queue.Len())
 	})
 
-	t.Run("Pop returns last value given stack with value", func(t *testing.T) {
-		stack := newMaxStack[int]()
-		stack.Push(44)
-		assert.Equal(t, 44, *stack.Pop())
+	t.Run("Clear removes all values", func(t *testing.T) {
+		queue := newQueue[int]()
+		queue.Enqueue(44)
+		queue.Clear()
+		assert.True(t, queue.IsEmpty())
+		queue.Enqueue(12)
+		value, _ := queue.TryDequeue()
+		assert.Equal(t, 12, value)
 	})
+}
 
-	t.Run("Pop returns max value given stack with two values", func(t *testing.T) {
-		stack := newMaxStack[int]()
-		stack.Push(108)
-		stack.Push(44)
-		assert.Equal(t, 108, *stack.Pick())
-		assert.Equal(t, 108, *stack.Pop())
+func TestQueueOnTwoStacksValueAPI(t *testing.T) {
+	queue := newQueueOnTwoStacks[int]()
+	_, ok := queue.TryDequeue()
+	assert.False(t, ok)
 
-		assert.Equal(t, 44, *stack.Pick())
-		assert.Equal(t, 44, *stack.Pop())
+	queue.Enqueue(44)
+	queue.Enqueue(12)
+	value, _ := queue.Peek()
+	assert.Equal(t, 44, value)
+	queue.Enqueue(14)
+	assert.Equal(t, 3, queue.Len())
 
-		assert.Nil(t, stack.Pick())
-		assert.Nil(t, stack.Pop())
-	})
+	value, _ = queue.TryDequeue()
+	assert.Equal(t, 44, value)
+	assert.Equal(t, 2, queue.Len())
 
-	t.Run("Pop returns max value given stack with multiple values", func(t *testing.T) {
-		stack := newMaxStack[int]()
-		stack.Push(44)
-		stack.Push(15)
-		stack.Push(108)
-		stack.Push(55)
-		stack.Push(12)
-		assert.Equal(t, 108, *stack.Pick())
-		assert.Equal(t, 108, *stack.Pop())
+	queue.Clear()
+	assert.True(t, queue.IsEmpty())
+	assert.Equal(t, 0, queue.Len())
+}
 
-		assert.Equal(t, 55, *stack.Pick())
-		assert.Equal(t, 55, *stack.Pop())
+func TestRingQueue(t *testing.T) {
+	t.Run("Peek reports no value given empty queue", func(t *testing.T) {
+		queue := newRingQueue[int]()
+		_, ok := queue.Peek()
+		assert.False(t, ok)
+		assert.True(t, queue.IsEmpty())
+	})
 
-		assert.Equal(t, 44, *stack.Pick())
-		assert.Equal(t, 44, *stack.Pop())
+	t.Run("TryDequeue returns first value given queue with multiple values", func(t *testing.T) {
+		queue := newRingQueue[int]()
+		queue.Enqueue(44)
+		queue.Enqueue(12)
+		queue.Enqueue(14)
+		value, _ := queue.Peek()
+		assert.Equal(t, 44, value)
+		value, _ = queue.TryDequeue()
+		assert.Equal(t, 44, value)
+		value, _ = queue.TryDequeue()
+		assert.Equal(t, 12, value)
+		value, _ = queue.TryDequeue()
+		assert.Equal(t, 14, value)
+		_, ok := queue.TryDequeue()
+		assert.False(t, ok)
+	})
 
-		assert.Equal(t, 15, *stack.Pick())
-		assert.Equal(t, 15, *stack.Pop())
+	t.Run("Keeps order when growing wrapped buffer", func(t *testing.T) {
+		queue := newRingQueueWithCapacity[int](3)
+		queue.Enqueue(1)
+		queue.Enqueue(2)
+		queue.Enqueue(3)
+		value, _ := queue.TryDequeue()
+		assert.Equal(t, 1, value)
+		queue.Enqueue(4)
+		queue.Enqueue(5)
+		assert.Equal(t, 4, queue.Len())
+		assert.Equal(t, 6, queue.Cap())
+		value, _ = queue.TryDequeue()
+		assert.Equal(t, 2, value)
+		value, _ = queue.TryDequeue()
+		assert.Equal(t, 3, value)
+		value, _ = queue.TryDequeue()
+		assert.Equal(t, 4, value)
+		value, _ = queue.TryDequeue()
+		assert.Equal(t, 5, value)
+		assert.True(t, queue.IsEmpty())
+	})
 
-		assert.Equal(t, 12, *stack.Pick())
-		assert.Equal(t, 12, *stack.Pop())
+	t.Run("Peek and TryDequeue return values", func(t *testing.T) {
+		queue := newRingQueue[int]()
+		_, ok := queue.Peek()
+		assert.False(t, ok)
 
-		assert.Nil(t, stack.Pick())
-		assert.Nil(t, stack.Pop())
+		queue.Enqueue(44)
+		value, ok := queue.Peek()
+		assert.True(t, ok)
+		assert.Equal(t, 44, value)
+
+		value, ok = queue.TryDequeue()
+		assert.True(t, ok)
+		assert.Equal(t, 44, value)
+		_, ok = queue.TryDequeue()
+		assert.False(t, ok)
 	})
 }
 
-func TestShelter(t *testing.T) {
-	t.Run("Pick returns nil given empty shelter", func(t *testing.T) {
-		shelter := newShelter()
-		assert.Nil(t, shelter.Pick())
+func TestQueueImplementations(t *testing.T) {
+	queues := map[string]func() QueueInterface[int]{
+		"Queue":            func() QueueInterface[int] { return newQueue[int]() },
+		"RingQueue":        func() QueueInterface[int] { return newRingQueue[int]() },
+		"QueueOnTwoStacks": func() QueueInterface[int] { return newQueueOnTwoStacks[int]() },
+		"DequeQueue":       func() QueueInterface[int] { return newDequeQueue[int]() },
+	}
+
+	for name, createQueue := range queues {
+		createQueue := createQueue
+
+		t.Run(name+" keeps FIFO order given interleaved operations", func(t *testing.T) {
+			queue := createQueue()
+			expected := 0
+			for value := 0; value < 100; value++ {
+				queue.Enqueue(value)
+				if value%3 == 0 {
+					dequeued, _ := queue.TryDequeue()
+					assert.Equal(t, expected, dequeued)
+					expected++
+				}
+			}
+			for !queue.IsEmpty() {
+				dequeued, _ := queue.TryDequeue()
+				assert.Equal(t, expected, dequeued)
+				expected++
+			}
+			assert.Equal(t, 100, expected)
+			_, ok := queue.TryDequeue()
+			assert.False(t, ok)
+		})
+	}
+}
+
+func BenchmarkQueueEnqueue(b *testing.B) {
+	queues := map[string]func() QueueInterface[int]{
+		"Queue":     func() QueueInterface[int] { return newQueue[int]() },
+		"RingQueue": func() QueueInterface[int] { return newRingQueue[int]() },
+	}
+
+	for name, createQueue := range queues {
+		for _, size := range []int{1000, 10000, 100000} {
+			createQueue := createQueue
+			size := size
+
+			b.Run(fmt.Sprintf("%s/%d", name, size), func(b *testing.B) {
+				b.ReportAllocs()
+				for i := 0; i < b.N; i++ {
+					queue := createQueue()
+					for value := 0; value < size; value++ {
+						queue.Enqueue(value)
+					}
+				}
+			})
+		}
+	}
+}
+
+func TestBlockingQueue(t *testing.T) {
+	t.Run("Error when creating queue without capacity", func(t *testing.T) {
+		queue, err := newBlockingQueue[int](0)
+		assert.Nil(t, queue)
+		assert.NotNil(t, err)
 	})
 
-	t.Run("Pick returns first animal given shelter with one cat", func(t *testing.T) {
-		shelter := newShelter()
-		cat := newCat("Cat1")
-		shelter.Enqueue(cat)
-		assert.Equal(t, "Cat1", shelter.Pick().GetName())
+	t.Run("Take returns values in FIFO order", func(t *testing.T) {
+		queue, _ := newBlockingQueue[int](3)
+		assert.Nil(t, queue.Put(context.Background(), 44))
+		assert.Nil(t, queue.Put(context.Background(), 12))
+		assert.Equal(t, 2, queue.Len())
+
+		value, err := queue.Take(context.Background())
+		assert.Nil(t, err)
+		assert.Equal(t, 44, value)
+
+		value, err = queue.Take(context.Background())
+		assert.Nil(t, err)
+		assert.Equal(t, 12, value)
 	})
 
-	t.Run("DequeueAny returns first animal given shelter with one cat", func(t *testing.T) {
-		shelter := newShelter()
-		cat := newCat("Cat1")
-		shelter.Enqueue(cat)
-		assert.Equal(t, "Cat1", shelter.DequeueAny().GetName())
+	t.Run("TryPut returns error given full queue", func(t *testing.T) {
+		queue, _ := newBlockingQueue[int](1)
+		assert.Nil(t, queue.TryPut(1))
+		assert.Equal(t, ErrQueueFull, queue.TryPut(2))
 	})
 
-	t.Run("Pick returns first animal given shelter with one dog", func(t *testing.T) {
-		shelter := newShelter()
-		dog := newDog("Dog1")
-		shelter.Enqueue(dog)
-		assert.Equal(t, "Dog1", shelter.Pick().GetName())
+	t.Run("TryTake returns error given empty queue", func(t *testing.T) {
+		queue, _ := newBlockingQueue[int](1)
+		_, err := queue.TryTake()
+		assert.Equal(t, ErrQueueEmpty, err)
 	})
 
-	t.Run("DequeueAny returns first animal given shelter with one dog", func(t *testing.T) {
-		shelter := newShelter()
-		dog := newDog("Dog1")
-		shelter.Enqueue(dog)
-		assert.Equal(t, "Dog1", shelter.DequeueAny().GetName())
+	t.Run("Put blocks until value is taken", func(t *testing.T) {
+		queue, _ := newBlockingQueue[int](1)
+		_ = queue.TryPut(1)
+
+		putDone := make(chan error)
+		go func() {
+			putDone <- queue.Put(context.Background(), 2)
+		}()
+
+		select {
+		case <-putDone:
+			t.Fatal("Put returned given full queue")
+		case <-time.After(20 * time.Millisecond):
+		}
+
+		value, _ := queue.TryTake()
+		assert.Equal(t, 1, value)
+		assert.Nil(t, <-putDone)
+
+		value, _ = queue.TryTake()
+		assert.Equal(t, 2, value)
+	})
+
+	t.Run("Take blocks until value is put", func(t *testing.T) {
+		queue, _ := newBlockingQueue[int](1)
+
+		go func() {
+			time.Sleep(20 * time.Millisecond)
+			_ = queue.TryPut(44)
+		}()
+
+		value, err := queue.Take(context.Background())
+		assert.Nil(t, err)
+		assert.Equal(t, 44, value)
+	})
+
+	t.Run("Put returns context error given cancelled context", func(t *testing.T) {
+		queue, _ := newBlockingQueue[int](1)
+		_ = queue.TryPut(1)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		assert.Equal(t, context.DeadlineExceeded, queue.Put(ctx, 2))
+		assert.Equal(t, 1, queue.Len())
+	})
+
+	t.Run("Take returns context error given cancelled context", func(t *testing.T) {
+		queue, _ := newBlockingQueue[int](1)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		_, err := queue.Take(ctx)
+		assert.Equal(t, context.Canceled, err)
+	})
+
+	t.Run("Close wakes up blocked Take", func(t *testing.T) {
+		queue, _ := newBlockingQueue[int](1)
+
+		go func() {
+			time.Sleep(20 * time.Millisecond)
+			queue.Close()
+		}()
+
+		_, err := queue.Take(context.Background())
+		assert.Equal(t, ErrQueueClosed, err)
+	})
+
+	t.Run("Closed queue rejects Put but lets remaining values be taken", func(t *testing.T) {
+		queue, _ := newBlockingQueue[int](2)
+		_ = queue.TryPut(1)
+		queue.Close()
+
+		assert.True(t, queue.IsClosed())
+		assert.Equal(t, ErrQueueClosed, queue.Put(context.Background(), 2))
+
+		value, err := queue.Take(context.Background())
+		assert.Nil(t, err)
+		assert.Equal(t, 1, value)
+
+		_, err = queue.TryTake()
+		assert.Equal(t, ErrQueueClosed, err)
+	})
+
+	t.Run("Drain returns remaining values and unblocks producers", func(t *testing.T) {
+		queue, _ := newBlockingQueue[int](2)
+		_ = queue.TryPut(1)
+		_ = queue.TryPut(2)
+
+		putDone := make(chan error)
+		go func() {
+			putDone <- queue.Put(context.Background(), 3)
+		}()
+		time.Sleep(10 * time.Millisecond)
+
+		drained := queue.Drain()
+		assert.Nil(t, <-putDone)
+		queue.Close()
+
+		drained = append(drained, queue.Drain()...)
+		assert.Equal(t, []int{1, 2, 3}, drained)
+		assert.Equal(t, 0, queue.Len())
+	})
+
+	t.Run("Delivers every value once given concurrent producers and consumers", func(t *testing.T) {
+		queue, _ := newBlockingQueue[int](16)
+		var producers sync.WaitGroup
+		var consumers sync.WaitGroup
+
+		for worker := 0; worker < stressWorkers; worker++ {
+			producers.Add(1)
+			go func(worker int) {
+				defer producers.Done()
+				for i := 0; i < stressValuesPerWorker; i++ {
+					_ = queue.Put(context.Background(), worker*stressValuesPerWorker+i)
+				}
+			}(worker)
+		}
+
+		taken := make([][]int, stressWorkers)
+		for worker := 0; worker < stressWorkers; worker++ {
+			consumers.Add(1)
+			go func(worker int) {
+				defer consumers.Done()
+				for {
+					value, err := queue.Take(context.Background())
+					if err != nil {
+						return
+					}
+					taken[worker] = append(taken[worker], value)
+				}
+			}(worker)
+		}
+
+		producers.Wait()
+		queue.Close()
+		consumers.Wait()
+
+		assertEveryValuePoppedOnce(t, taken)
+	})
+}
+
+func TestDeque(t *testing.T) {
+	t.Run("Peek and Pop report empty deque", func(t *testing.T) {
+		deque := newDeque[int]()
+		_, ok := deque.PeekFront()
+		assert.False(t, ok)
+		_, ok = deque.PeekBack()
+		assert.False(t, ok)
+		_, ok = deque.PopFront()
+		assert.False(t, ok)
+		_, ok = deque.PopBack()
+		assert.False(t, ok)
+		assert.True(t, deque.IsEmpty())
+	})
+
+	t.Run("Pushes and pops at both ends", func(t *testing.T) {
+		deque := newDeque[int]()
+		deque.PushBack(2)
+		deque.PushFront(1)
+		deque.PushBack(3)
+
+		front, _ := deque.PeekFront()
+		back, _ := deque.PeekBack()
+		assert.Equal(t, 1, front)
+		assert.Equal(t, 3, back)
+		assert.Equal(t, 3, deque.Len())
+
+		value, _ := deque.PopBack()
+		assert.Equal(t, 3, value)
+		value, _ = deque.PopFront()
+		assert.Equal(t, 1, value)
+		value, _ = deque.PopFront()
+		assert.Equal(t, 2, value)
+		assert.True(t, deque.IsEmpty())
+	})
+
+	t.Run("At and Set access values by index", func(t *testing.T) {
+		deque := newDeque[int]()
+		for value := 0; value < 200; value++ {
+			deque.PushFront(value)
+		}
+
+		value, ok := deque.At(0)
+		assert.True(t, ok)
+		assert.Equal(t, 199, value)
+
+		assert.True(t, deque.Set(150, -1))
+		value, _ = deque.At(150)
+		assert.Equal(t, -1, value)
+
+		_, ok = deque.At(200)
+		assert.False(t, ok)
+		assert.False(t, deque.Set(-1, 0))
+	})
+
+	t.Run("Matches slice given mixed operations across chunks", func(t *testing.T) {
+		deque := newDeque[int]()
+		expected := []int{}
+		seed := 7
+
+		for step := 0; step < 20000; step++ {
+			seed = (seed*1103515245 + 12345) % 2147483648
+			switch seed % 5 {
+			case 0, 1:
+				deque.PushBack(step)
+				expected = append(expected, step)
+			case 2:
+				deque.PushFront(step)
+				expected = append([]int{step}, expected...)
+			case 3:
+				value, ok := deque.PopBack()
+				assert.Equal(t, len(expected) != 0, ok)
+				if ok {
+					assert.Equal(t, expected[len(expected)-1], value)
+					expected = expected[:len(expected)-1]
+				}
+			case 4:
+				value, ok := deque.PopFront()
+				assert.Equal(t, len(expected) != 0, ok)
+				if ok {
+					assert.Equal(t, expected[0], value)
+					expected = expected[1:]
+				}
+			}
+		}
+
+		assert.Equal(t, len(expected), deque.Len())
+		for index, value := range expected {
+			actual, _ := deque.At(index)
+			assert.Equal(t, value, actual)
+		}
+	})
+
+	t.Run("Keeps chunk map bounded given queue usage", func(t *testing.T) {
+		deque := newDeque[int]()
+		for value := 0; value < 100000; value++ {
+			deque.PushBack(value)
+			if value >= 100 {
+				_, _ = deque.PopFront()
+			}
+		}
+
+		assert.Equal(t, 100, deque.Len())
+		assert.Less(t, len(deque.chunks), 16)
+	})
+}
+
+func TestDequeStack(t *testing.T) {
+	stack := newDequeStack[int]()
+	_, ok := stack.Peek()
+	assert.False(t, ok)
+	stack.Push(44)
+	stack.Push(12)
+	value, _ := stack.TryPop()
+	assert.Equal(t, 12, value)
+	value, _ = stack.TryPop()
+	assert.Equal(t, 44, value)
+	_, ok = stack.TryPop()
+	assert.False(t, ok)
+	assert.True(t, stack.IsEmpty())
+}
+
+func TestMinStack(t *testing.T) {
+	t.Run("Return nil for min given empty stack", func(t *testing.T) {
+		stack := newMinStack[int]()
+		assert.Nil(t, stack.Min())
+	})
+
+	t.Run("Min returns minimum value given stack with multiple values", func(t *testing.T) {
+		stack := newMinStack[int]()
+		stack.Push(12)
+		stack.Push(44)
+		assert.Equal(t, 12, *stack.Min())
+	})
+
+	t.Run("Min returns minimum value given after poping up minimum value", func(t *testing.T) {
+		stack := newMinStack[int]()
+		stack.Push(15)
+		stack.Push(44)
+		stack.Push(12)
+		_ = stack.Pop()
+		assert.Equal(t, 15, *stack.Min())
+	})
+
+	t.Run("Min keeps duplicate minimum after popping one of them", func(t *testing.T) {
+		stack := newMinStack[int]()
+		stack.Push(15)
+		stack.Push(12)
+		stack.Push(12)
+		_ = stack.Pop()
+		assert.Equal(t, 12, *stack.Min())
+		_ = stack.Pop()
+		assert.Equal(t, 15, *stack.Min())
+	})
+
+	t.Run("Pop returns nil given empty stack", func(t *testing.T) {
+		stack := newMinStack[int]()
+		assert.Nil(t, stack.Pop())
+	})
+
+	t.Run("TryPop and TryMin report empty stack", func(t *testing.T) {
+		stack := newMinStack[int]()
+		_, ok := stack.TryPop()
+		assert.False(t, ok)
+		_, ok = stack.TryMin()
+		assert.False(t, ok)
+
+		stack.Push(7)
+		minimum, ok := stack.TryMin()
+		assert.True(t, ok)
+		assert.Equal(t, 7, minimum)
+
+		value, ok := stack.TryPop()
+		assert.True(t, ok)
+		assert.Equal(t, 7, value)
+	})
+}
+
+func TestMinStackValueAPI(t *testing.T) {
+	stack := newMinStack[int]()
+	_, ok := stack.Peek()
+	assert.False(t, ok)
+
+	stack.Push(15)
+	stack.Push(12)
+	value, _ := stack.Peek()
+	minimum, _ := stack.TryMin()
+	assert.Equal(t, 12, value)
+	assert.Equal(t, 12, minimum)
+	assert.Equal(t, 2, stack.Len())
+
+	stack.Clear()
+	assert.True(t, stack.IsEmpty())
+	_, ok = stack.TryMin()
+	assert.False(t, ok)
+}
+
+func TestMinMaxStack(t *testing.T) {
+	t.Run("Value API tracks length and clears extrema", func(t *testing.T) {
+		stack := newMinMaxStack[int]()
+		_, ok := stack.Peek()
+		assert.False(t, ok)
+
+		stack.Push(15)
+		stack.Push(3)
+		stack.Push(27)
+		top, _ := stack.Peek()
+		assert.Equal(t, 27, top)
+		assert.Equal(t, 3, stack.Len())
+
+		stack.Clear()
+		assert.True(t, stack.IsEmpty())
+		assert.Equal(t, 0, stack.Len())
+		_, ok = stack.TryMin()
+		assert.False(t, ok)
+		_, ok = stack.TryMax()
+		assert.False(t, ok)
+	})
+
+	t.Run("Min and Max report no value given empty stack", func(t *testing.T) {
+		stack := newMinMaxStack[int]()
+		_, ok := stack.TryMin()
+		assert.False(t, ok)
+		_, ok = stack.TryMax()
+		assert.False(t, ok)
+		_, ok = stack.TryPop()
+		assert.False(t, ok)
+		assert.True(t, stack.IsEmpty())
+	})
+
+	t.Run("Min and Max follow pushes and pops", func(t *testing.T) {
+		stack := newMinMaxStack[int]()
+		stack.Push(15)
+		stack.Push(44)
+		stack.Push(12)
+		stack.Push(44)
+		minimum, _ := stack.TryMin()
+		assert.Equal(t, 12, minimum)
+		maximum, _ := stack.TryMax()
+		assert.Equal(t, 44, maximum)
+		value, _ := stack.Peek()
+		assert.Equal(t, 44, value)
+
+		value, _ = stack.TryPop()
+		assert.Equal(t, 44, value)
+		maximum, _ = stack.TryMax()
+		assert.Equal(t, 44, maximum)
+
+		value, _ = stack.TryPop()
+		assert.Equal(t, 12, value)
+		minimum, _ = stack.TryMin()
+		assert.Equal(t, 15, minimum)
+
+		value, _ = stack.TryPop()
+		assert.Equal(t, 44, value)
+		maximum, _ = stack.TryMax()
+		minimum, _ = stack.TryMin()
+		assert.Equal(t, 15, maximum)
+		assert.Equal(t, 15, minimum)
+	})
+}
+
+func TestHanoiGame(t *testing.T) {
+
+	t.Run("Error when creating 0 elements tower", func(t *testing.T) {
+		game, err := newHanoiGame(0)
+		assert.Nil(t, game)
+		assert.NotNil(t, err)
+	})
+
+	t.Run("Check initial conditions", func(t *testing.T) {
+		game, err := newHanoiGame(5)
+		assert.Nil(t, err)
+		assert.Equal(t, 1, *game.sourceRod.Pop())
+		assert.Equal(t, 2, *game.sourceRod.Pop())
+		assert.Equal(t, 3, *game.sourceRod.Pop())
+		assert.Equal(t, 4, *game.sourceRod.Pop())
+		assert.Equal(t, 5, *game.sourceRod.Pop())
+		assert.True(t, game.spareRod.IsEmpty())
+		assert.True(t, game.destinationRod.IsEmpty())
+	})
+
+	t.Run("Solves game with 1 disk", func(t *testing.T) {
+		game, _ := newHanoiGame(1)
+		game.Solve()
+
+		assert.True(t, game.sourceRod.IsEmpty())
+		assert.True(t, game.spareRod.IsEmpty())
+		assert.Equal(t, 1, *game.destinationRod.Pop())
+	})
+
+	t.Run("Solves game with 2 disk", func(t *testing.T) {
+		game, _ := newHanoiGame(2)
+		game.Solve()
+
+		assert.True(t, game.sourceRod.IsEmpty())
+		assert.True(t, game.spareRod.IsEmpty())
+		assert.Equal(t, 1, *game.destinationRod.Pop())
+		assert.Equal(t, 2, *game.destinationRod.Pop())
+	})
+
+	t.Run("Solves game with 3 disk", func(t *testing.T) {
+		game, _ := newHanoiGame(3)
+		game.Solve()
+
+		assert.True(t, game.sourceRod.IsEmpty())
+		assert.True(t, game.spareRod.IsEmpty())
+		assert.Equal(t, 1, *game.destinationRod.Pop())
+		assert.Equal(t, 2, *game.destinationRod.Pop())
+		assert.Equal(t, 3, *game.destinationRod.Pop())
+	})
+
+	t.Run("Solves game with 3 disk", func(t *testing.T) {
+		game, _ := newHanoiGame(6)
+		game.Solve()
+
+		assert.True(t, game.sourceRod.IsEmpty())
+		assert.True(t, game.spareRod.IsEmpty())
+		assert.Equal(t, 1, *game.destinationRod.Pop())
+		assert.Equal(t, 2, *game.destinationRod.Pop())
+		assert.Equal(t, 3, *game.destinationRod.Pop())
+		assert.Equal(t, 4, *game.destinationRod.Pop())
+		assert.Equal(t, 5, *game.destinationRod.Pop())
+		assert.Equal(t, 6, *game.destinationRod.Pop())
+	})
+}
+
+func TestHanoiMoves(t *testing.T) {
+	t.Run("Returns zero moves for empty tower", func(t *testing.T) {
+		assert.Equal(t, 0, HanoiMoves(0, nil))
+	})
+
+	t.Run("Emits moves for 2 disk tower", func(t *testing.T) {
+		moves := []HanoiMove{}
+		count := HanoiMoves(2, func(move HanoiMove) {
+			moves = append(moves, move)
+		})
+
+		assert.Equal(t, 3, count)
+		assert.Equal(t, []HanoiMove{
+			{From: SourceRod, To: SpareRod},
+			{From: SourceRod, To: DestinationRod},
+			{From: SpareRod, To: DestinationRod},
+		}, moves)
+	})
+
+	t.Run("Returns 2^n - 1 moves", func(t *testing.T) {
+		for height := 1; height <= 16; height++ {
+			assert.Equal(t, 1<<height-1, HanoiMoves(height, nil))
+		}
+	})
+
+	t.Run("Emitted moves replay against fresh game", func(t *testing.T) {
+		for height := 1; height <= 10; height++ {
+			moves := []HanoiMove{}
+			HanoiMoves(height, func(move HanoiMove) {
+				moves = append(moves, move)
+			})
+
+			game, _ := newHanoiGame(height)
+			assert.Nil(t, game.Replay(moves))
+			assert.True(t, game.sourceRod.IsEmpty())
+			assert.True(t, game.spareRod.IsEmpty())
+			assert.Equal(t, height, game.destinationRod.Len())
+		}
+	})
+
+	t.Run("SolveWith emits the moves it applies", func(t *testing.T) {
+		game, _ := newHanoiGame(5)
+		moves := []HanoiMove{}
+		count, err := game.SolveWith(func(move HanoiMove) {
+			moves = append(moves, move)
+		})
+
+		assert.Nil(t, err)
+		assert.Equal(t, 31, count)
+		assert.Equal(t, count, len(moves))
+		assert.Equal(t, 5, game.destinationRod.Len())
+
+		replayed, _ := newHanoiGame(5)
+		assert.Nil(t, replayed.Replay(moves))
+		for disk := 1; disk <= 5; disk++ {
+			value, _ := replayed.destinationRod.TryPop()
+			assert.Equal(t, disk, value)
+		}
+	})
+
+	t.Run("Replay rejects illegal moves", func(t *testing.T) {
+		game, _ := newHanoiGame(3)
+
+		err := game.Replay([]HanoiMove{{From: SpareRod, To: DestinationRod}})
+		assert.ErrorIs(t, err, ErrHanoiEmptyRod)
+
+		err = game.Replay([]HanoiMove{
+			{From: SourceRod, To: DestinationRod},
+			{From: SourceRod, To: DestinationRod},
+		})
+		assert.ErrorIs(t, err, ErrHanoiLargerDisk)
+		assert.Contains(t, err.Error(), "Move 1 from source to destination")
+
+		err = game.Replay([]HanoiMove{{From: SourceRod, To: SourceRod}})
+		assert.ErrorIs(t, err, ErrHanoiSameRodMove)
+
+		err = game.Replay([]HanoiMove{{From: SourceRod, To: HanoiRod(7)}})
+		assert.ErrorIs(t, err, ErrHanoiInvalidRod)
+	})
+
+	t.Run("Replay records moves in history", func(t *testing.T) {
+		game, _ := newHanoiGame(3)
+		err := game.Replay([]HanoiMove{
+			{From: SourceRod, To: DestinationRod},
+			{From: SourceRod, To: SpareRod},
+		})
+
+		assert.Nil(t, err)
+		assert.Equal(t, 2, game.MoveCount())
+		assert.Nil(t, game.Undo())
+		assert.Equal(t, "1 | 3 2\n2 |\n3 | 1\n", game.String())
+	})
+
+	t.Run("Replay leaves game untouched given illegal move", func(t *testing.T) {
+		game, _ := newHanoiGame(3)
+		_ = game.MoveDisk(SourceRod, SpareRod)
+		_ = game.Undo()
+
+		err := game.Replay([]HanoiMove{
+			{From: SourceRod, To: DestinationRod},
+			{From: SourceRod, To: SpareRod},
+			{From: SourceRod, To: SpareRod},
+		})
+
+		assert.ErrorIs(t, err, ErrHanoiLargerDisk)
+		assert.Contains(t, err.Error(), "Move 2 from source to spare")
+		assert.Equal(t, "1 | 3 2 1\n2 |\n3 |\n", game.String())
+		assert.Equal(t, 0, game.MoveCount())
+		assert.Nil(t, game.Redo())
+	})
+}
+
+func TestHanoiGameMoves(t *testing.T) {
+	t.Run("MoveDisk moves top disk between rods", func(t *testing.T) {
+		game, _ := newHanoiGame(3)
+
+		assert.Nil(t, game.MoveDisk(SourceRod, DestinationRod))
+		assert.Nil(t, game.MoveDisk(SourceRod, SpareRod))
+		assert.Equal(t, "1 | 3\n2 | 2\n3 | 1\n", game.String())
+		assert.Equal(t, 2, game.MoveCount())
+	})
+
+	t.Run("MoveDisk validates rules", func(t *testing.T) {
+		game, _ := newHanoiGame(3)
+
+		assert.Equal(t, ErrHanoiEmptyRod, game.MoveDisk(SpareRod, SourceRod))
+		assert.Equal(t, ErrHanoiSameRodMove, game.MoveDisk(SourceRod, SourceRod))
+		assert.Equal(t, ErrHanoiInvalidRod, game.MoveDisk(SourceRod, HanoiRod(3)))
+		assert.Nil(t, game.MoveDisk(SourceRod, SpareRod))
+		assert.Equal(t, ErrHanoiLargerDisk, game.MoveDisk(SourceRod, SpareRod))
+		assert.Equal(t, 1, game.MoveCount())
+		assert.Equal(t, "1 | 3 2\n2 | 1\n3 |\n", game.String())
+	})
+
+	t.Run("IsSolved reports whole tower on destination rod", func(t *testing.T) {
+		game, _ := newHanoiGame(2)
+		assert.False(t, game.IsSolved())
+
+		_ = game.MoveDisk(SourceRod, SpareRod)
+		_ = game.MoveDisk(SourceRod, DestinationRod)
+		assert.False(t, game.IsSolved())
+
+		_ = game.MoveDisk(SpareRod, DestinationRod)
+		assert.True(t, game.IsSolved())
+	})
+
+	t.Run("Undo and redo replay moves", func(t *testing.T) {
+		game, _ := newHanoiGame(3)
+		assert.Equal(t, ErrHanoiNoUndo, game.Undo())
+		assert.Equal(t, ErrHanoiNoRedo, game.Redo())
+
+		_ = game.MoveDisk(SourceRod, DestinationRod)
+		_ = game.MoveDisk(SourceRod, SpareRod)
+
+		assert.Nil(t, game.Undo())
+		assert.Equal(t, "1 | 3 2\n2 |\n3 | 1\n", game.String())
+		assert.Nil(t, game.Undo())
+		assert.Equal(t, "1 | 3 2 1\n2 |\n3 |\n", game.String())
+		assert.Equal(t, 0, game.MoveCount())
+
+		assert.Nil(t, game.Redo())
+		assert.Equal(t, "1 | 3 2\n2 |\n3 | 1\n", game.String())
+		assert.Equal(t, 1, game.MoveCount())
+	})
+
+	t.Run("New move clears redo history", func(t *testing.T) {
+		game, _ := newHanoiGame(3)
+		_ = game.MoveDisk(SourceRod, DestinationRod)
+		_ = game.Undo()
+
+		_ = game.MoveDisk(SourceRod, SpareRod)
+		assert.Equal(t, ErrHanoiNoRedo, game.Redo())
+	})
+
+	t.Run("Solver moves can be undone", func(t *testing.T) {
+		game, _ := newHanoiGame(4)
+		count, err := game.Solve()
+		assert.Nil(t, err)
+		assert.Equal(t, 15, count)
+		assert.True(t, game.IsSolved())
+		assert.Equal(t, 15, game.MoveCount())
+
+		for game.Undo() == nil {
+		}
+		assert.Equal(t, "1 | 4 3 2 1\n2 |\n3 |\n", game.String())
+	})
+
+	t.Run("Solve returns error after manual move", func(t *testing.T) {
+		game, _ := newHanoiGame(3)
+		_ = game.MoveDisk(SourceRod, SpareRod)
+
+		count, err := game.Solve()
+		assert.Equal(t, ErrHanoiNotAtStart, err)
+		assert.Equal(t, 0, count)
+		assert.False(t, game.IsSolved())
+		assert.Equal(t, 1, game.MoveCount())
+		assert.Equal(t, "1 | 3 2\n2 | 1\n3 |\n", game.String())
+	})
+
+	t.Run("Solve works again once manual moves are undone", func(t *testing.T) {
+		game, _ := newHanoiGame(3)
+		_ = game.MoveDisk(SourceRod, SpareRod)
+		_ = game.Undo()
+
+		count, err := game.Solve()
+		assert.Nil(t, err)
+		assert.Equal(t, 7, count)
+		assert.True(t, game.IsSolved())
+		assert.Equal(t, ErrHanoiNoRedo, game.Redo())
+	})
+}
+
+func TestPlayHanoi(t *testing.T) {
+	t.Run("Plays game until solved", func(t *testing.T) {
+		game, _ := newHanoiGame(2)
+		var output strings.Builder
+
+		err := playHanoi(game, strings.NewReader("1 2\nsource destination\n2 3\n"), &output)
+		assert.Nil(t, err)
+		assert.True(t, game.IsSolved())
+		assert.Contains(t, output.String(), "Solved in 3 moves")
+	})
+
+	t.Run("Reports invalid commands and moves", func(t *testing.T) {
+		game, _ := newHanoiGame(2)
+		var output strings.Builder
+
+		err := playHanoi(game, strings.NewReader("2 3\n1 4\njump\n1 3\n1 3\nundo\nredo\nq\n"), &output)
+		assert.Nil(t, err)
+		assert.Contains(t, output.String(), "Error: "+ErrHanoiEmptyRod.Error())
+		assert.Contains(t, output.String(), "Error: "+ErrHanoiInvalidRod.Error()+": 4")
+		assert.Contains(t, output.String(), `Error: Unknown command "jump"`)
+		assert.Contains(t, output.String(), "Error: "+ErrHanoiLargerDisk.Error())
+		assert.Equal(t, 1, game.MoveCount())
+		assert.False(t, game.IsSolved())
+	})
+
+	t.Run("Stops at end of input", func(t *testing.T) {
+		game, _ := newHanoiGame(3)
+		var output strings.Builder
+
+		assert.Nil(t, playHanoi(game, strings.NewReader("help\n"), &output))
+		assert.Equal(t, 2, strings.Count(output.String(), "Commands:"))
+	})
+}
+
+func TestQueueOnTwoStacks(t *testing.T) {
+	t.Run("Pick returns nil given empty queue", func(t *testing.T) {
+		queue := newQueueOnTwoStacks[int]()
+		assert.Nil(t, queue.Pick())
+	})
+
+	t.Run("Pick returns first value given queue with value", func(t *testing.T) {
+		queue := newQueueOnTwoStacks[int]()
+		queue.Enqueue(44)
+		assert.Equal(t, 44, *queue.Pick())
+	})
+
+	t.Run("Dequeue returns first value given queue with value", func(t *testing.T) {
+		queue := newQueueOnTwoStacks[int]()
+		queue.Enqueue(44)
+		assert.Equal(t, 44, *queue.Dequeue())
+	})
+
+	t.Run("Dequeue returns first value given queue with multiple values", func(t *testing.T) {
+		queue := newQueueOnTwoStacks[int]()
+		queue.Enqueue(44)
+		queue.Enqueue(12)
+		assert.Equal(t, 44, *queue.Dequeue())
+		assert.Equal(t, 12, *queue.Dequeue())
+		assert.Nil(t, queue.Dequeue())
+	})
+}
+
+func TestMinMaxQueue(t *testing.T) {
+	t.Run("Value API keeps FIFO order and extrema", func(t *testing.T) {
+		queue := newMinMaxQueue[int]()
+		_, ok := queue.Peek()
+		assert.False(t, ok)
+		_, ok = queue.TryDequeue()
+		assert.False(t, ok)
+		_, ok = queue.TryMin()
+		assert.False(t, ok)
+
+		queue.Enqueue(15)
+		queue.Enqueue(3)
+		queue.Enqueue(27)
+		assert.Equal(t, 3, queue.Len())
+
+		first, _ := queue.Peek()
+		assert.Equal(t, 15, first)
+		first, _ = queue.TryDequeue()
+		assert.Equal(t, 15, first)
+		queue.Enqueue(1)
+
+		minimum, _ := queue.TryMin()
+		maximum, _ := queue.TryMax()
+		assert.Equal(t, 1, minimum)
+		assert.Equal(t, 27, maximum)
+		assert.Equal(t, 3, queue.Len())
+
+		queue.Clear()
+		assert.True(t, queue.IsEmpty())
+		assert.Equal(t, 0, queue.Len())
+		_, ok = queue.TryMax()
+		assert.False(t, ok)
+	})
+
+	t.Run("Min and Max report no value given empty queue", func(t *testing.T) {
+		queue := newMinMaxQueue[int]()
+		_, ok := queue.TryMin()
+		assert.False(t, ok)
+		_, ok = queue.TryMax()
+		assert.False(t, ok)
+		_, ok = queue.Peek()
+		assert.False(t, ok)
+		_, ok = queue.TryDequeue()
+		assert.False(t, ok)
+		assert.True(t, queue.IsEmpty())
+	})
+
+	t.Run("Min and Max follow enqueues and dequeues", func(t *testing.T) {
+		queue := newMinMaxQueue[int]()
+		queue.Enqueue(15)
+		queue.Enqueue(3)
+		queue.Enqueue(44)
+		minimum, _ := queue.TryMin()
+		assert.Equal(t, 3, minimum)
+		maximum, _ := queue.TryMax()
+		assert.Equal(t, 44, maximum)
+
+		value, _ := queue.TryDequeue()
+		assert.Equal(t, 15, value)
+		queue.Enqueue(1)
+		minimum, _ = queue.TryMin()
+		assert.Equal(t, 1, minimum)
+		maximum, _ = queue.TryMax()
+		assert.Equal(t, 44, maximum)
+
+		value, _ = queue.TryDequeue()
+		assert.Equal(t, 3, value)
+		value, _ = queue.TryDequeue()
+		assert.Equal(t, 44, value)
+		minimum, _ = queue.TryMin()
+		maximum, _ = queue.TryMax()
+		assert.Equal(t, 1, minimum)
+		assert.Equal(t, 1, maximum)
+	})
+}
+
+func TestSlidingWindow(t *testing.T) {
+	t.Run("Error given empty window", func(t *testing.T) {
+		_, _, err := SlidingWindow([]int{1, 2}, 0)
+		assert.NotNil(t, err)
+	})
+
+	t.Run("Returns no windows given window bigger than input", func(t *testing.T) {
+		minima, maxima, err := SlidingWindow([]int{1, 2}, 3)
+		assert.Nil(t, err)
+		assert.Empty(t, minima)
+		assert.Empty(t, maxima)
+	})
+
+	t.Run("Returns minima and maxima of every window", func(t *testing.T) {
+		minima, maxima, err := SlidingWindow([]int{1, 3, -1, -3, 5, 3, 6, 7}, 3)
+		assert.Nil(t, err)
+		assert.Equal(t, []int{-1, -3, -3, -3, 3, 3}, minima)
+		assert.Equal(t, []int{3, 3, 5, 5, 6, 7}, maxima)
+	})
+
+	t.Run("Window of one returns input", func(t *testing.T) {
+		minima, maxima, _ := SlidingWindow([]float64{2.5, 1.5, 3.5}, 1)
+		assert.Equal(t, []float64{2.5, 1.5, 3.5}, minima)
+		assert.Equal(t, []float64{2.5, 1.5, 3.5}, maxima)
+	})
+}
+
+func TestMaxStack(t *testing.T) {
+	t.Run("Pick returns nil given empty stack", func(t *testing.T) {
+		stack := newMaxStack[int]()
+		assert.Nil(t, stack.Pick())
+		assert.Nil(t, stack.Max())
+		_, ok := stack.TryPopMax()
+		assert.False(t, ok)
+		assert.True(t, stack.IsEmpty())
+	})
+
+	t.Run("Pick returns last value given stack with value", func(t *testing.T) {
+		stack := newMaxStack[int]()
+		stack.Push(44)
+		assert.Equal(t, 44, *stack.Pick())
+	})
+
+	t.Run("Pop returns last value given stack with value", func(t *testing.T) {
+		stack := newMaxStack[int]()
+		stack.Push(44)
+		assert.Equal(t, 44, *stack.Pop())
+	})
+
+	t.Run("Pop returns last pushed value given stack with two values", func(t *testing.T) {
+		stack := newMaxStack[int]()
+		stack.Push(108)
+		stack.Push(44)
+		assert.Equal(t, 44, *stack.Pick())
+		assert.Equal(t, 108, *stack.Max())
+		assert.Equal(t, 44, *stack.Pop())
+
+		assert.Equal(t, 108, *stack.Pick())
+		assert.Equal(t, 108, *stack.Max())
+		assert.Equal(t, 108, *stack.Pop())
+
+		assert.Nil(t, stack.Pick())
+		assert.Nil(t, stack.Max())
+		assert.Nil(t, stack.Pop())
+	})
+
+	t.Run("Max follows pops given stack with multiple values", func(t *testing.T) {
+		stack := newMaxStack[int]()
+		stack.Push(44)
+		stack.Push(15)
+		stack.Push(108)
+		stack.Push(55)
+		stack.Push(12)
+		assert.Equal(t, 108, *stack.Max())
+
+		assert.Equal(t, 12, *stack.Pop())
+		assert.Equal(t, 55, *stack.Pop())
+		assert.Equal(t, 108, *stack.Max())
+
+		assert.Equal(t, 108, *stack.Pop())
+		assert.Equal(t, 44, *stack.Max())
+
+		assert.Equal(t, 15, *stack.Pop())
+		assert.Equal(t, 44, *stack.Pop())
+		assert.True(t, stack.IsEmpty())
+	})
+
+	t.Run("PopMax removes maximum and keeps order of other values", func(t *testing.T) {
+		stack := newMaxStack[int]()
+		stack.Push(44)
+		stack.Push(15)
+		stack.Push(108)
+		stack.Push(55)
+		stack.Push(12)
+
+		maximum, _ := stack.TryPopMax()
+		assert.Equal(t, 108, maximum)
+		assert.Equal(t, 55, *stack.Max())
+		assert.Equal(t, 12, *stack.Pick())
+
+		maximum, _ = stack.TryPopMax()
+		assert.Equal(t, 55, maximum)
+		assert.Equal(t, 12, *stack.Pop())
+		assert.Equal(t, 15, *stack.Pop())
+		maximum, _ = stack.TryPopMax()
+		assert.Equal(t, 44, maximum)
+		assert.True(t, stack.IsEmpty())
+	})
+
+	t.Run("PopMax removes top-most maximum given duplicates", func(t *testing.T) {
+		stack := newMaxStack[int]()
+		stack.Push(5)
+		stack.Push(1)
+		stack.Push(5)
+		stack.Push(2)
+
+		maximum, _ := stack.TryPopMax()
+		assert.Equal(t, 5, maximum)
+		assert.Equal(t, 2, *stack.Pop())
+		assert.Equal(t, 1, *stack.Pop())
+		assert.Equal(t, 5, *stack.Max())
+		assert.Equal(t, 5, *stack.Pop())
+		assert.Nil(t, stack.Max())
+	})
+}
+
+func TestMaxStackValueAPI(t *testing.T) {
+	stack := newMaxStack[int]()
+	_, ok := stack.Peek()
+	assert.False(t, ok)
+	_, ok = stack.TryPopMax()
+	assert.False(t, ok)
+
+	stack.Push(44)
+	stack.Push(108)
+	stack.Push(12)
+	assert.Equal(t, 3, stack.Len())
+
+	value, _ := stack.Peek()
+	maximum, _ := stack.TryMax()
+	assert.Equal(t, 12, value)
+	assert.Equal(t, 108, maximum)
+
+	maximum, _ = stack.TryPopMax()
+	assert.Equal(t, 108, maximum)
+	value, _ = stack.TryPop()
+	assert.Equal(t, 12, value)
+	assert.Equal(t, 1, stack.Len())
+
+	stack.Clear()
+	assert.True(t, stack.IsEmpty())
+	_, ok = stack.TryMax()
+	assert.False(t, ok)
+	stack.Push(7)
+	maximum, _ = stack.TryMax()
+	assert.Equal(t, 7, maximum)
+}
+
+func TestPriorityQueue(t *testing.T) {
+	t.Run("Peek reports no value given empty queue", func(t *testing.T) {
+		queue := newMinPriorityQueue[int]()
+		_, ok := queue.Peek()
+		assert.False(t, ok)
+		_, ok = queue.TryPop()
+		assert.False(t, ok)
+		assert.True(t, queue.IsEmpty())
+	})
+
+	t.Run("Min queue pops values in ascending order", func(t *testing.T) {
+		queue := newMinPriorityQueue[int]()
+		for _, value := range []int{44, 15, 108, 55, 12, 15} {
+			queue.Push(value)
+		}
+
+		assert.Equal(t, 6, queue.Len())
+		value, _ := queue.Peek()
+		assert.Equal(t, 12, value)
+		assert.Equal(t, []int{12, 15, 15, 44, 55, 108}, popAll(queue))
+	})
+
+	t.Run("Max queue pops values in descending order", func(t *testing.T) {
+		queue := newMaxPriorityQueue[int]()
+		for _, value := range []int{44, 15, 108, 55, 12} {
+			queue.Push(value)
+		}
+
+		assert.Equal(t, []int{108, 55, 44, 15, 12}, popAll(queue))
+	})
+
+	t.Run("Peek and TryPop return values", func(t *testing.T) {
+		queue := newMinPriorityQueue[int]()
+		_, ok := queue.Peek()
+		assert.False(t, ok)
+		_, ok = queue.TryPop()
+		assert.False(t, ok)
+
+		queue.Push(7)
+		queue.Push(5)
+		value, ok := queue.Peek()
+		assert.True(t, ok)
+		assert.Equal(t, 5, value)
+		value, ok = queue.TryPop()
+		assert.True(t, ok)
+		assert.Equal(t, 5, value)
+		assert.Equal(t, 1, queue.Len())
+	})
+
+	t.Run("Custom comparator orders structs", func(t *testing.T) {
+		type task struct {
+			name     string
+			priority int
+		}
+		queue := newPriorityQueue(func(a, b task) bool {
+			return a.priority > b.priority
+		})
+		queue.Push(task{name: "low", priority: 1})
+		queue.Push(task{name: "high", priority: 10})
+		queue.Push(task{name: "medium", priority: 5})
+
+		assert.Equal(t, []task{
+			{name: "high", priority: 10},
+			{name: "medium", priority: 5},
+			{name: "low", priority: 1},
+		}, popAll(queue))
+	})
+
+	t.Run("D-ary queues pop values in order", func(t *testing.T) {
+		for arity := 2; arity <= 5; arity++ {
+			queue, err := newDaryPriorityQueue(arity, func(a, b int) bool {
+				return a < b
+			})
+			assert.Nil(t, err)
+
+			for value := 0; value < 100; value++ {
+				queue.Push((value * 37) % 100)
+			}
+
+			popped := popAll(queue)
+			for value := 0; value < 100; value++ {
+				assert.Equal(t, value, popped[value])
+			}
+		}
+	})
+
+	t.Run("Error when creating unary queue", func(t *testing.T) {
+		queue, err := newDaryPriorityQueue(1, func(a, b int) bool {
+			return a < b
+		})
+		assert.Nil(t, queue)
+		assert.NotNil(t, err)
 	})
+}
 
-	t.Run("DequeueDog returns nil given shelter with only cats", func(t *testing.T) {
-		shelter := newShelter()
-		cat := newCat("Cat1")
-		shelter.Enqueue(cat)
-		assert.Nil(t, shelter.DequeueDog())
+func popAll[T any](queue *PriorityQueue[T]) []T {
+	values := []T{}
+	for value, ok := queue.TryPop(); ok; value, ok = queue.TryPop() {
+		values = append(values, value)
+	}
+	return values
+}
+
+func TestIndexedHeap(t *testing.T) {
+	lessInt := func(a, b int) bool {
+		return a < b
+	}
+
+	t.Run("Pop returns nil given empty heap", func(t *testing.T) {
+		heap := newIndexedHeap(lessInt)
+		assert.Nil(t, heap.Pick())
+		assert.Nil(t, heap.Pop())
+		assert.True(t, heap.IsEmpty())
+	})
+
+	t.Run("Pop returns items in order", func(t *testing.T) {
+		heap := newIndexedHeap(lessInt)
+		heap.Push(44)
+		heap.Push(12)
+		heap.Push(108)
+
+		assert.Equal(t, 12, heap.Pick().Value())
+		assert.Equal(t, 12, heap.Pop().Value())
+		assert.Equal(t, 44, heap.Pop().Value())
+		assert.Equal(t, 108, heap.Pop().Value())
+	})
+
+	t.Run("Value of handle is a copy that cannot reorder the heap", func(t *testing.T) {
+		heap := newIndexedHeap(lessInt)
+		heap.Push(5)
+		heap.Push(7)
+
+		value := heap.Pick().Value()
+		value = 100
+		assert.Equal(t, 100, value)
+
+		assert.Equal(t, 5, heap.Pop().Value())
+		assert.Equal(t, 7, heap.Pop().Value())
+	})
+
+	t.Run("Update moves item given changed priority", func(t *testing.T) {
+		heap := newIndexedHeap(lessInt)
+		heap.Push(44)
+		item := heap.Push(108)
+		heap.Push(12)
+
+		assert.Nil(t, heap.Update(item, 1))
+		assert.Equal(t, item, heap.Pick())
+
+		assert.Nil(t, heap.Update(item, 200))
+		assert.Equal(t, 12, heap.Pop().Value())
+		assert.Equal(t, 44, heap.Pop().Value())
+		assert.Equal(t, 200, heap.Pop().Value())
+	})
+
+	t.Run("Remove deletes item from the middle", func(t *testing.T) {
+		heap, _ := newDaryIndexedHeap(3, lessInt)
+		items := []*HeapItem[int]{}
+		for value := 0; value < 20; value++ {
+			items = append(items, heap.Push((value*7)%20))
+		}
+
+		for _, item := range items {
+			if item.Value()%2 == 1 {
+				assert.Nil(t, heap.Remove(item))
+				assert.False(t, heap.Contains(item))
+			}
+		}
+
+		assert.Equal(t, 10, heap.Len())
+		for value := 0; value < 20; value += 2 {
+			assert.Equal(t, value, heap.Pop().Value())
+		}
+	})
+
+	t.Run("Error given item which was already removed", func(t *testing.T) {
+		heap := newIndexedHeap(lessInt)
+		item := heap.Push(44)
+		_ = heap.Pop()
+
+		assert.Equal(t, ErrHeapItemNotFound, heap.Remove(item))
+		assert.Equal(t, ErrHeapItemNotFound, heap.Update(item, 1))
+	})
+
+	t.Run("Error given item from other heap", func(t *testing.T) {
+		heap := newIndexedHeap(lessInt)
+		otherHeap := newIndexedHeap(lessInt)
+		heap.Push(44)
+		item := otherHeap.Push(12)
+
+		assert.Equal(t, ErrHeapItemNotFound, heap.Remove(item))
+		assert.Equal(t, 1, heap.Len())
 	})
+}
 
-	t.Run("DequeueDog returns first dog given shelter with multiple animals", func(t *testing.T) {
+func TestShelter(t *testing.T) {
+	t.Run("RegisterSpecies rejects nil prototype and empty name", func(t *testing.T) {
 		shelter := newShelter()
-		cat1 := newCat("Cat1")
-		shelter.Enqueue(cat1)
-		dog := newDog("Dog1")
-		shelter.Enqueue(dog)
-		cat2 := newCat("Cat2")
-		shelter.Enqueue(cat2)
-		assert.Equal(t, "Dog1", shelter.DequeueDog().GetName())
-		assert.Equal(t, "Cat1", shelter.DequeueAny().GetName())
-		assert.Equal(t, "Cat2", shelter.DequeueAny().GetName())
+		assert.Equal(t, ErrInvalidSpecies, shelter.RegisterSpecies("cat", nil))
+		assert.Equal(t, ErrInvalidSpecies, shelter.RegisterSpecies("", &Cat{}))
+		assert.Empty(t, shelter.Species())
 	})
 
-	t.Run("DequeueCat returns nil given shelter with only dogs", func(t *testing.T) {
+	t.Run("RegisterSpecies rejects name reserved for adopting any animal", func(t *testing.T) {
 		shelter := newShelter()
+		assert.Equal(t, ErrReservedSpecies, shelter.RegisterSpecies("any", &Cat{}))
+		assert.Empty(t, shelter.Species())
+	})
+
+	t.Run("Enqueue rejects nil animals", func(t *testing.T) {
+		shelter := newCatAndDogShelter()
+		assert.Equal(t, ErrNilAnimal, shelter.Enqueue(nil))
+		assert.Equal(t, ErrNilAnimal, shelter.Enqueue((*Cat)(nil)))
+		assert.Equal(t, 0, shelter.Len())
+	})
+
+	t.Run("Admit and Adopt return shelter entries", func(t *testing.T) {
+		shelter := newCatAndDogShelter()
+		arrival := time.Date(2022, 1, 2, 3, 4, 5, 0, time.UTC)
+		shelter.now = func() time.Time {
+			return arrival
+		}
+
+		entry, err := shelter.Admit(newCat("Cat1"))
+		assert.Nil(t, err)
+		assert.Equal(t, "cat", entry.Species)
+		assert.Equal(t, uint64(0), entry.Sequence)
+		assert.Equal(t, arrival, entry.ArrivedAt)
+		entry, _ = shelter.Admit(newDog("Dog1"))
+		assert.Equal(t, uint64(1), entry.Sequence)
+
+		adoption, err := shelter.AdoptSpecies("dog")
+		assert.Nil(t, err)
+		assert.Equal(t, "Dog1", adoption.Animal.GetName())
+		assert.Equal(t, arrival, adoption.AdoptedAt)
+
+		adoption, err = shelter.AdoptAny()
+		assert.Nil(t, err)
+		assert.Equal(t, "Cat1", adoption.Animal.GetName())
+
+		_, err = shelter.AdoptAny()
+		assert.Equal(t, ErrShelterEmpty, err)
+		_, err = shelter.AdoptSpecies("rabbit")
+		assert.Equal(t, ErrUnknownSpecies, err)
+	})
+
+	t.Run("Adopted records do not pile up behind a waiting animal", func(t *testing.T) {
+		shelter := newCatAndDogShelter()
+		_ = shelter.Enqueue(newCat("Cat1"))
+		for i := 0; i < 10000; i++ {
+			_ = shelter.Enqueue(newDog("Dog"))
+			_, err := shelter.AdoptSpecies("dog")
+			assert.Nil(t, err)
+		}
+
+		assert.Equal(t, 1, shelter.Len())
+		assert.LessOrEqual(t, shelter.arrivals.records.Len(), 2)
+		assert.Equal(t, 0, shelter.species["dog"].records.Len())
+	})
+
+	t.Run("AdoptAny prunes the species queue of adopted animal", func(t *testing.T) {
+		shelter := newCatAndDogShelter()
+		for i := 0; i < 10000; i++ {
+			_ = shelter.Enqueue(newCat("Cat"))
+			_, err := shelter.AdoptAny()
+			assert.Nil(t, err)
+		}
+
+		assert.Equal(t, 0, shelter.arrivals.records.Len())
+		assert.Equal(t, 0, shelter.species["cat"].records.Len())
+	})
+
+	t.Run("Animals adopted by species are skipped by DequeueAny", func(t *testing.T) {
+		shelter := newCatAndDogShelter()
+		_ = shelter.Enqueue(newCat("Cat1"))
+		_ = shelter.Enqueue(newDog("Dog1"))
+		_ = shelter.Enqueue(newCat("Cat2"))
+
+		cat, _ := shelter.DequeueSpecies("cat")
+		assert.Equal(t, "Cat1", cat.GetName())
+
+		animal, _ := shelter.Peek()
+		assert.Equal(t, "Dog1", animal.GetName())
+		animal, _ = shelter.DequeueAny()
+		assert.Equal(t, "Dog1", animal.GetName())
+		animal, _ = shelter.DequeueAny()
+		assert.Equal(t, "Cat2", animal.GetName())
+		_, err := shelter.DequeueAny()
+		assert.Equal(t, ErrShelterEmpty, err)
+	})
+
+	t.Run("Animals adopted in any order are skipped by DequeueSpecies", func(t *testing.T) {
+		shelter := newCatAndDogShelter()
+		_ = shelter.Enqueue(newDog("Dog1"))
+		_ = shelter.Enqueue(newCat("Cat1"))
+		_ = shelter.Enqueue(newDog("Dog2"))
+		_ = shelter.Enqueue(newDog("Dog3"))
+
+		_, _ = shelter.DequeueAny()
+		_, _ = shelter.DequeueMatching(func(animal IAnimal) bool {
+			return animal.GetName() == "Dog2"
+		})
+
+		assert.Equal(t, 1, shelter.CountSpecies("dog"))
+		dog, _ := shelter.DequeueSpecies("dog")
+		assert.Equal(t, "Dog3", dog.GetName())
+		_, err := shelter.DequeueSpecies("dog")
+		assert.Equal(t, ErrShelterEmpty, err)
+		assert.Equal(t, 1, shelter.Len())
+	})
+
+	t.Run("Peek returns error given empty shelter", func(t *testing.T) {
+		shelter := newCatAndDogShelter()
+		animal, err := shelter.Peek()
+		assert.Nil(t, animal)
+		assert.Equal(t, ErrShelterEmpty, err)
+	})
+
+	t.Run("Peek returns first animal given shelter with one cat", func(t *testing.T) {
+		shelter := newCatAndDogShelter()
+		cat := newCat("Cat1")
+		_ = shelter.Enqueue(cat)
+		animal, _ := shelter.Peek()
+		assert.Equal(t, "Cat1", animal.GetName())
+	})
+
+	t.Run("DequeueAny returns first animal given shelter with one cat", func(t *testing.T) {
+		shelter := newCatAndDogShelter()
+		cat := newCat("Cat1")
+		_ = shelter.Enqueue(cat)
+		animal, err := shelter.DequeueAny()
+		assert.Nil(t, err)
+		assert.Equal(t, "Cat1", animal.GetName())
+	})
+
+	t.Run("DequeueAny returns first animal given shelter with one dog", func(t *testing.T) {
+		shelter := newCatAndDogShelter()
 		dog := newDog("Dog1")
-		shelter.Enqueue(dog)
-		assert.Nil(t, shelter.DequeueCat())
+		_ = shelter.Enqueue(dog)
+		animal, _ := shelter.DequeueAny()
+		assert.Equal(t, "Dog1", animal.GetName())
+	})
+
+	t.Run("DequeueAny returns error given empty shelter", func(t *testing.T) {
+		shelter := newCatAndDogShelter()
+		animal, err := shelter.DequeueAny()
+		assert.Nil(t, animal)
+		assert.Equal(t, ErrShelterEmpty, err)
+	})
+
+	t.Run("DequeueSpecies returns error given shelter with only cats", func(t *testing.T) {
+		shelter := newCatAndDogShelter()
+		cat := newCat("Cat1")
+		_ = shelter.Enqueue(cat)
+		animal, err := shelter.DequeueSpecies("dog")
+		assert.Nil(t, animal)
+		assert.Equal(t, ErrShelterEmpty, err)
+	})
+
+	t.Run("DequeueSpecies returns first dog given shelter with multiple animals", func(t *testing.T) {
+		shelter := newCatAndDogShelter()
+		_ = shelter.Enqueue(newCat("Cat1"))
+		_ = shelter.Enqueue(newDog("Dog1"))
+		_ = shelter.Enqueue(newCat("Cat2"))
+
+		animal, _ := shelter.DequeueSpecies("dog")
+		assert.Equal(t, "Dog1", animal.GetName())
+		animal, _ = shelter.DequeueAny()
+		assert.Equal(t, "Cat1", animal.GetName())
+		animal, _ = shelter.DequeueAny()
+		assert.Equal(t, "Cat2", animal.GetName())
+	})
+
+	t.Run("DequeueSpecific returns typed first cat given shelter with multiple animals", func(t *testing.T) {
+		shelter := newCatAndDogShelter()
+		_ = shelter.Enqueue(newDog("Dog1"))
+		_ = shelter.Enqueue(newCat("Cat1"))
+		_ = shelter.Enqueue(newDog("Dog2"))
+
+		cat, err := DequeueSpecific[*Cat](shelter)
+		assert.Nil(t, err)
+		assert.Equal(t, "Cat1", cat.GetName())
+
+		animal, _ := shelter.DequeueAny()
+		assert.Equal(t, "Dog1", animal.GetName())
+		animal, _ = shelter.DequeueAny()
+		assert.Equal(t, "Dog2", animal.GetName())
+
+		_, err = DequeueSpecific[*Cat](shelter)
+		assert.Equal(t, ErrShelterEmpty, err)
 	})
 
-	t.Run("DequeueCat returns first cat given shelter with multiple animals", func(t *testing.T) {
+	t.Run("Enqueue after adopting last animal keeps order", func(t *testing.T) {
+		shelter := newCatAndDogShelter()
+		_ = shelter.Enqueue(newCat("Cat1"))
+		_ = shelter.Enqueue(newDog("Dog1"))
+		animal, _ := shelter.DequeueSpecies("dog")
+		assert.Equal(t, "Dog1", animal.GetName())
+		_ = shelter.Enqueue(newDog("Dog2"))
+
+		animal, _ = shelter.DequeueAny()
+		assert.Equal(t, "Cat1", animal.GetName())
+		animal, _ = shelter.DequeueAny()
+		assert.Equal(t, "Dog2", animal.GetName())
+		assert.Equal(t, 0, shelter.Len())
+	})
+
+	t.Run("Enqueue returns error given unregistered species", func(t *testing.T) {
 		shelter := newShelter()
-		dog1 := newDog("Dog1")
-		shelter.Enqueue(dog1)
+		assert.Equal(t, ErrUnknownSpecies, shelter.Enqueue(newCat("Cat1")))
+
+		_, err := shelter.DequeueSpecies("cat")
+		assert.Equal(t, ErrUnknownSpecies, err)
+		_, err = DequeueSpecific[*Dog](shelter)
+		assert.Equal(t, ErrUnknownSpecies, err)
+	})
+
+	t.Run("RegisterSpecies returns error given duplicate registration", func(t *testing.T) {
+		shelter := newCatAndDogShelter()
+		assert.Equal(t, ErrSpeciesRegistered, shelter.RegisterSpecies("cat", &Dog{}))
+		assert.Equal(t, ErrSpeciesRegistered, shelter.RegisterSpecies("kitten", &Cat{}))
+		assert.Equal(t, []string{"cat", "dog"}, shelter.Species())
+	})
+
+	t.Run("Species registered at runtime get own queue", func(t *testing.T) {
+		type Rabbit struct {
+			Animal
+		}
+		shelter := newCatAndDogShelter()
+		assert.Nil(t, shelter.RegisterSpecies("rabbit", &Rabbit{}))
+
+		_ = shelter.Enqueue(newDog("Dog1"))
+		_ = shelter.Enqueue(&Rabbit{Animal{Name: "Rabbit1"}})
+		_ = shelter.Enqueue(newCat("Cat1"))
+		assert.Equal(t, 1, shelter.CountSpecies("rabbit"))
+
+		rabbit, err := DequeueSpecific[*Rabbit](shelter)
+		assert.Nil(t, err)
+		assert.Equal(t, "Rabbit1", rabbit.GetName())
+		assert.Equal(t, 0, shelter.CountSpecies("rabbit"))
+	})
+
+	t.Run("Animals lists arrivals with sequence and timestamp", func(t *testing.T) {
+		shelter := newCatAndDogShelter()
+		arrival := time.Date(2022, 1, 2, 3, 4, 5, 0, time.UTC)
+		shelter.now = func() time.Time {
+			return arrival
+		}
+
+		_ = shelter.Enqueue(newCat("Cat1"))
+		_ = shelter.Enqueue(newDog("Dog1"))
+		_ = shelter.Enqueue(newCat("Cat2"))
+		_, _ = shelter.DequeueSpecies("dog")
+
+		entries := shelter.Animals()
+		assert.Equal(t, 2, len(entries))
+		assert.Equal(t, "Cat1", entries[0].Animal.GetName())
+		assert.Equal(t, "cat", entries[0].Species)
+		assert.Equal(t, uint64(0), entries[0].Sequence)
+		assert.Equal(t, uint64(2), entries[1].Sequence)
+		assert.Equal(t, arrival, entries[1].ArrivedAt)
+	})
+}
+
+func TestAnimalAttributes(t *testing.T) {
+	intake := time.Date(2022, 3, 4, 0, 0, 0, 0, time.UTC)
+	dog := &Dog{Animal{Name: "Rex", Age: 3, Breed: "Beagle", Size: SizeMedium, IntakeDate: intake}}
+
+	t.Run("Animal exposes its attributes", func(t *testing.T) {
+		var animal IAnimal = dog
+		assert.Equal(t, "Rex", animal.GetName())
+		assert.Equal(t, 3, animal.GetAge())
+		assert.Equal(t, "Beagle", animal.GetBreed())
+		assert.Equal(t, SizeMedium, animal.GetSize())
+		assert.Equal(t, intake, animal.GetIntakeDate())
+	})
+
+	t.Run("Size is encoded as text", func(t *testing.T) {
+		data, err := json.Marshal(dog)
+		assert.Nil(t, err)
+		assert.Contains(t, string(data), `"size":"medium"`)
+
+		var decoded Dog
+		assert.Nil(t, json.Unmarshal(data, &decoded))
+		assert.Equal(t, *dog, decoded)
+	})
+
+	t.Run("Invalid size is rejected", func(t *testing.T) {
+		var decoded Dog
+		assert.NotNil(t, json.Unmarshal([]byte(`{"size":"huge"}`), &decoded))
+
+		_, err := json.Marshal(&Dog{Animal{Size: AnimalSize(42)}})
+		assert.NotNil(t, err)
+		assert.Equal(t, "AnimalSize(42)", AnimalSize(42).String())
+	})
+}
+
+func TestShelterIntakeDate(t *testing.T) {
+	arrival := time.Date(2022, 5, 6, 7, 8, 9, 0, time.UTC)
+	shelter := newCatAndDogShelter()
+	shelter.now = func() time.Time {
+		return arrival
+	}
+
+	t.Run("Defaults to arrival time", func(t *testing.T) {
 		cat := newCat("Cat1")
-		shelter.Enqueue(cat)
-		dog2 := newDog("Dog2")
-		shelter.Enqueue(dog2)
-		assert.Equal(t, "Cat1", shelter.DequeueCat().GetName())
-		assert.Equal(t, "Dog1", shelter.DequeueAny().GetName())
-		assert.Equal(t, "Dog2", shelter.DequeueAny().GetName())
+		entry, _ := shelter.Admit(cat)
+		assert.Equal(t, arrival, cat.GetIntakeDate())
+		assert.Equal(t, entry.ArrivedAt, entry.Animal.GetIntakeDate())
+	})
+
+	t.Run("Keeps earlier intake date of transferred animal", func(t *testing.T) {
+		intake := time.Date(2021, 12, 1, 0, 0, 0, 0, time.UTC)
+		dog := &Dog{Animal{Name: "Dog1", IntakeDate: intake}}
+		entry, _ := shelter.Admit(dog)
+		assert.Equal(t, intake, dog.GetIntakeDate())
+		assert.Equal(t, arrival, entry.ArrivedAt)
+	})
+}
+
+func TestShelterDequeueMatching(t *testing.T) {
+	newAttributedShelter := func() *Shelter {
+		shelter := newCatAndDogShelter()
+		_ = shelter.Enqueue(&Dog{Animal{Name: "Dog1", Age: 9, Breed: "Beagle", Size: SizeMedium}})
+		_ = shelter.Enqueue(&Cat{Animal{Name: "Cat1", Age: 1, Breed: "Siamese", Size: SizeSmall}})
+		_ = shelter.Enqueue(&Dog{Animal{Name: "Dog2", Age: 2, Breed: "Beagle", Size: SizeMedium}})
+		_ = shelter.Enqueue(&Dog{Animal{Name: "Dog3", Age: 1, Breed: "Mastiff", Size: SizeLarge}})
+		return shelter
+	}
+
+	t.Run("Adopts the oldest animal satisfying the predicate", func(t *testing.T) {
+		shelter := newAttributedShelter()
+
+		animal, err := shelter.DequeueMatching(func(animal IAnimal) bool {
+			return animal.GetBreed() == "Beagle" && animal.GetAge() < 5
+		})
+		assert.Nil(t, err)
+		assert.Equal(t, "Dog2", animal.GetName())
+		assert.Equal(t, 3, shelter.Len())
+		assert.Equal(t, 2, shelter.CountSpecies("dog"))
+	})
+
+	t.Run("Predicate can combine type and attributes", func(t *testing.T) {
+		shelter := newAttributedShelter()
+
+		animal, err := shelter.DequeueMatching(func(animal IAnimal) bool {
+			_, isDog := animal.(*Dog)
+			return isDog && animal.GetSize() != SizeMedium
+		})
+		assert.Nil(t, err)
+		assert.Equal(t, "Dog3", animal.GetName())
+
+		next, _ := shelter.DequeueAny()
+		assert.Equal(t, "Dog1", next.GetName())
 	})
+
+	t.Run("Adoption is recorded in history", func(t *testing.T) {
+		shelter := newAttributedShelter()
+
+		_, _ = shelter.DequeueMatching(func(animal IAnimal) bool {
+			return animal.GetSize() == SizeSmall
+		})
+		records := shelter.Adoptions(AdoptionQuery{})
+		assert.Equal(t, 1, len(records))
+		assert.Equal(t, "cat", records[0].Species)
+	})
+
+	t.Run("Returns error given nil predicate", func(t *testing.T) {
+		shelter := newAttributedShelter()
+
+		animal, err := shelter.DequeueMatching(nil)
+		assert.Nil(t, animal)
+		assert.Equal(t, ErrNilPredicate, err)
+		assert.Equal(t, 4, shelter.Len())
+	})
+
+	t.Run("Returns error when nothing matches", func(t *testing.T) {
+		shelter := newAttributedShelter()
+
+		animal, err := shelter.DequeueMatching(func(animal IAnimal) bool {
+			return animal.GetAge() > 10
+		})
+		assert.Nil(t, animal)
+		assert.Equal(t, ErrNoMatchingAnimal, err)
+		assert.Equal(t, 4, shelter.Len())
+	})
+}
+
+func TestShelterPersistence(t *testing.T) {
+	newPopulatedShelter := func() *Shelter {
+		shelter := newCatAndDogShelter()
+		clock := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+		shelter.now = func() time.Time {
+			clock = clock.Add(time.Hour)
+			return clock
+		}
+
+		_ = shelter.Enqueue(newCat("Cat1"))
+		_ = shelter.Enqueue(newDog("Dog1"))
+		_ = shelter.Enqueue(&Cat{Animal{Name: "Cat2", Age: 4, Breed: "Siamese", Size: SizeSmall, IntakeDate: clock}})
+		_ = shelter.Enqueue(newDog("Dog2"))
+		_, _ = shelter.DequeueAny()
+		_, _ = shelter.DequeueSpecies("dog")
+		return shelter
+	}
+
+	codecs := []struct {
+		name      string
+		marshal   func(*Shelter) ([]byte, error)
+		unmarshal func(*Shelter, []byte) error
+	}{
+		{"JSON", (*Shelter).MarshalJSON, (*Shelter).UnmarshalJSON},
+		{"binary", (*Shelter).MarshalBinary, (*Shelter).UnmarshalBinary},
+	}
+
+	for _, codec := range codecs {
+		t.Run(codec.name+" snapshot restores animals with their concrete types", func(t *testing.T) {
+			original := newPopulatedShelter()
+			data, err := codec.marshal(original)
+			assert.Nil(t, err)
+
+			restored := newCatAndDogShelter()
+			assert.Nil(t, codec.unmarshal(restored, data))
+
+			assert.Equal(t, original.Animals(), restored.Animals())
+			assert.Equal(t, original.Adoptions(AdoptionQuery{}), restored.Adoptions(AdoptionQuery{}))
+
+			cat, err := DequeueSpecific[*Cat](restored)
+			assert.Nil(t, err)
+			assert.Equal(t, "Cat2", cat.GetName())
+			dog, err := DequeueSpecific[*Dog](restored)
+			assert.Nil(t, err)
+			assert.Equal(t, "Dog2", dog.GetName())
+		})
+
+		t.Run(codec.name+" snapshot continues the arrival sequence", func(t *testing.T) {
+			data, _ := codec.marshal(newPopulatedShelter())
+			restored := newCatAndDogShelter()
+			_ = codec.unmarshal(restored, data)
+
+			_ = restored.Enqueue(newCat("Cat3"))
+			entries := restored.Animals()
+			assert.Equal(t, uint64(4), entries[len(entries)-1].Sequence)
+		})
+
+		t.Run(codec.name+" snapshot needs the species to be registered", func(t *testing.T) {
+			data, _ := codec.marshal(newPopulatedShelter())
+			restored := newShelter()
+			_ = restored.RegisterSpecies("cat", &Cat{})
+			_ = restored.Enqueue(newCat("Resident"))
+
+			err := codec.unmarshal(restored, data)
+			assert.ErrorIs(t, err, ErrUnknownSpecies)
+			assert.Equal(t, 1, restored.Len())
+		})
+	}
+
+	t.Run("JSON snapshot only records adoption time for adopted animals", func(t *testing.T) {
+		data, err := newPopulatedShelter().MarshalJSON()
+		assert.Nil(t, err)
+
+		var snapshot struct {
+			Animals   []map[string]json.RawMessage
+			Adoptions []map[string]json.RawMessage
+		}
+		assert.Nil(t, json.Unmarshal(data, &snapshot))
+		assert.Equal(t, 2, len(snapshot.Animals))
+		for _, animal := range snapshot.Animals {
+			assert.NotContains(t, animal, "AdoptedAt")
+		}
+		assert.Equal(t, 2, len(snapshot.Adoptions))
+		for _, adoption := range snapshot.Adoptions {
+			assert.Contains(t, adoption, "AdoptedAt")
+		}
+	})
+
+	t.Run("Restoring adoption without adoption time returns error", func(t *testing.T) {
+		shelter := newCatAndDogShelter()
+		data := `{"NextSequence":1,"Animals":[],"Adoptions":[{"Species":"cat","Sequence":0,"ArrivedAt":"2022-01-01T00:00:00Z","Animal":{"name":"Cat1"}}]}`
+		assert.NotNil(t, shelter.UnmarshalJSON([]byte(data)))
+		assert.Empty(t, shelter.Adoptions(AdoptionQuery{}))
+	})
+
+	t.Run("Restoring invalid JSON returns error", func(t *testing.T) {
+		shelter := newCatAndDogShelter()
+		assert.NotNil(t, shelter.UnmarshalJSON([]byte("{")))
+	})
+}
+
+func TestShelterAdoptions(t *testing.T) {
+	shelter := newCatAndDogShelter()
+	start := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := start
+	shelter.now = func() time.Time {
+		clock = clock.Add(time.Hour)
+		return clock
+	}
+
+	_ = shelter.Enqueue(newCat("Cat1"))
+	_ = shelter.Enqueue(newDog("Dog1"))
+	_ = shelter.Enqueue(newCat("Cat2"))
+	_, _ = shelter.DequeueSpecies("dog")
+	_, _ = shelter.DequeueAny()
+	_, _ = shelter.DequeueAny()
+
+	t.Run("Every adoption is recorded in order", func(t *testing.T) {
+		records := shelter.Adoptions(AdoptionQuery{})
+		assert.Equal(t, 3, len(records))
+		assert.Equal(t, "Dog1", records[0].Animal.GetName())
+		assert.Equal(t, "Cat1", records[1].Animal.GetName())
+		assert.Equal(t, "Cat2", records[2].Animal.GetName())
+		assert.Equal(t, start.Add(4*time.Hour), records[0].AdoptedAt)
+		assert.Equal(t, start.Add(time.Hour), records[1].ArrivedAt)
+	})
+
+	t.Run("Adoptions can be filtered by species", func(t *testing.T) {
+		records := shelter.Adoptions(AdoptionQuery{Species: "cat"})
+		assert.Equal(t, 2, len(records))
+		assert.Equal(t, "Cat1", records[0].Animal.GetName())
+	})
+
+	t.Run("Adoptions can be filtered by name", func(t *testing.T) {
+		records := shelter.Adoptions(AdoptionQuery{Name: "Cat2"})
+		assert.Equal(t, 1, len(records))
+		assert.Equal(t, uint64(2), records[0].Sequence)
+	})
+
+	t.Run("Adoptions can be filtered by time range", func(t *testing.T) {
+		records := shelter.Adoptions(AdoptionQuery{
+			Since: start.Add(5 * time.Hour),
+			Until: start.Add(6 * time.Hour),
+		})
+		assert.Equal(t, 1, len(records))
+		assert.Equal(t, "Cat1", records[0].Animal.GetName())
+	})
+
+	t.Run("Returned records do not alias the log", func(t *testing.T) {
+		records := shelter.Adoptions(AdoptionQuery{})
+		records[0] = AdoptionRecord{}
+		assert.Equal(t, "Dog1", shelter.Adoptions(AdoptionQuery{})[0].Animal.GetName())
+	})
+}
+
+func TestShelterHandler(t *testing.T) {
+	newServer := func() *httptest.Server {
+		shelter := newSyncShelter()
+		_ = shelter.RegisterSpecies("cat", &Cat{})
+		_ = shelter.RegisterSpecies("dog", &Dog{})
+		return httptest.NewServer(newShelterHandler(shelter))
+	}
+	send := func(t *testing.T, method, url, body string) (int, []byte) {
+		request, err := http.NewRequest(method, url, strings.NewReader(body))
+		assert.Nil(t, err)
+		response, err := http.DefaultClient.Do(request)
+		assert.Nil(t, err)
+		defer response.Body.Close()
+		content, err := io.ReadAll(response.Body)
+		assert.Nil(t, err)
+		assert.Equal(t, "application/json", response.Header.Get("Content-Type"))
+		return response.StatusCode, content
+	}
+
+	t.Run("POST /animals admits an animal of a registered species", func(t *testing.T) {
+		server := newServer()
+		defer server.Close()
+
+		status, body := send(t, http.MethodPost, server.URL+"/animals", `{"species":"cat","name":"Cat1"}`)
+		assert.Equal(t, http.StatusCreated, status)
+
+		var animal struct {
+			Species  string `json:"species"`
+			Name     string `json:"name"`
+			Sequence uint64 `json:"sequence"`
+		}
+		assert.Nil(t, json.Unmarshal(body, &animal))
+		assert.Equal(t, "cat", animal.Species)
+		assert.Equal(t, "Cat1", animal.Name)
+		assert.Equal(t, uint64(0), animal.Sequence)
+	})
+
+	t.Run("POST /animals accepts animal attributes", func(t *testing.T) {
+		server := newServer()
+		defer server.Close()
+
+		status, _ := send(t, http.MethodPost, server.URL+"/animals",
+			`{"species":"dog","name":"Rex","age":3,"breed":"Beagle","size":"medium","intakeDate":"2022-03-04T00:00:00Z"}`)
+		assert.Equal(t, http.StatusCreated, status)
+
+		status, body := send(t, http.MethodPost, server.URL+"/adopt/dog", "")
+		assert.Equal(t, http.StatusOK, status)
+		assert.Contains(t, string(body), `"animal":{"name":"Rex","age":3,"breed":"Beagle","size":"medium","intakeDate":"2022-03-04T00:00:00Z"}`)
+
+		var adopted struct {
+			Animal Dog `json:"animal"`
+		}
+		assert.Nil(t, json.Unmarshal(body, &adopted))
+		assert.Equal(t, 3, adopted.Animal.GetAge())
+		assert.Equal(t, "Beagle", adopted.Animal.GetBreed())
+		assert.Equal(t, SizeMedium, adopted.Animal.GetSize())
+		assert.Equal(t, time.Date(2022, 3, 4, 0, 0, 0, 0, time.UTC), adopted.Animal.GetIntakeDate())
+
+		status, _ = send(t, http.MethodPost, server.URL+"/animals", `{"species":"dog","name":"Rex","size":"huge"}`)
+		assert.Equal(t, http.StatusBadRequest, status)
+	})
+
+	t.Run("POST /animals rejects oversized bodies", func(t *testing.T) {
+		server := newServer()
+		defer server.Close()
+
+		name := strings.Repeat("a", maxShelterRequestSize)
+		status, _ := send(t, http.MethodPost, server.URL+"/animals", `{"species":"cat","name":"`+name+`"}`)
+		assert.Equal(t, http.StatusBadRequest, status)
+
+		_, body := send(t, http.MethodGet, server.URL+"/animals", "")
+		assert.JSONEq(t, `[]`, string(body))
+	})
+
+	t.Run("POST /animals rejects unknown species and invalid JSON", func(t *testing.T) {
+		server := newServer()
+		defer server.Close()
+
+		status, body := send(t, http.MethodPost, server.URL+"/animals", `{"species":"rabbit","name":"Rabbit1"}`)
+		assert.Equal(t, http.StatusBadRequest, status)
+		assert.Contains(t, string(body), ErrUnknownSpecies.Error())
+
+		status, _ = send(t, http.MethodPost, server.URL+"/animals", `{"species":`)
+		assert.Equal(t, http.StatusBadRequest, status)
+	})
+
+	t.Run("GET /animals lists animals in arrival order", func(t *testing.T) {
+		server := newServer()
+		defer server.Close()
+
+		status, body := send(t, http.MethodGet, server.URL+"/animals", "")
+		assert.Equal(t, http.StatusOK, status)
+		assert.JSONEq(t, `[]`, string(body))
+
+		send(t, http.MethodPost, server.URL+"/animals", `{"species":"dog","name":"Dog1"}`)
+		send(t, http.MethodPost, server.URL+"/animals", `{"species":"cat","name":"Cat1"}`)
+
+		status, body = send(t, http.MethodGet, server.URL+"/animals", "")
+		assert.Equal(t, http.StatusOK, status)
+
+		var animals []struct {
+			Species string `json:"species"`
+			Name    string `json:"name"`
+		}
+		assert.Nil(t, json.Unmarshal(body, &animals))
+		assert.Equal(t, 2, len(animals))
+		assert.Equal(t, "dog", animals[0].Species)
+		assert.Equal(t, "Dog1", animals[0].Name)
+		assert.Equal(t, "Cat1", animals[1].Name)
+	})
+
+	t.Run("POST /adopt/any adopts the oldest animal", func(t *testing.T) {
+		server := newServer()
+		defer server.Close()
+
+		send(t, http.MethodPost, server.URL+"/animals", `{"species":"dog","name":"Dog1"}`)
+		send(t, http.MethodPost, server.URL+"/animals", `{"species":"cat","name":"Cat1"}`)
+
+		status, body := send(t, http.MethodPost, server.URL+"/adopt/any", "")
+		assert.Equal(t, http.StatusOK, status)
+
+		var adopted struct {
+			Species   string     `json:"species"`
+			Name      string     `json:"name"`
+			AdoptedAt *time.Time `json:"adoptedAt"`
+		}
+		assert.Nil(t, json.Unmarshal(body, &adopted))
+		assert.Equal(t, "dog", adopted.Species)
+		assert.Equal(t, "Dog1", adopted.Name)
+		assert.NotNil(t, adopted.AdoptedAt)
+	})
+
+	t.Run("POST /adopt/{species} adopts the oldest animal of that species", func(t *testing.T) {
+		server := newServer()
+		defer server.Close()
+
+		send(t, http.MethodPost, server.URL+"/animals", `{"species":"dog","name":"Dog1"}`)
+		send(t, http.MethodPost, server.URL+"/animals", `{"species":"cat","name":"Cat1"}`)
+		send(t, http.MethodPost, server.URL+"/animals", `{"species":"cat","name":"Cat2"}`)
+
+		status, body := send(t, http.MethodPost, server.URL+"/adopt/cat", "")
+		assert.Equal(t, http.StatusOK, status)
+		assert.Contains(t, string(body), `"name":"Cat1"`)
+
+		_, body = send(t, http.MethodGet, server.URL+"/animals", "")
+		assert.NotContains(t, string(body), `"Cat1"`)
+		assert.Contains(t, string(body), `"Cat2"`)
+	})
+
+	t.Run("POST /adopt returns 409 when no animal of a kind is available", func(t *testing.T) {
+		server := newServer()
+		defer server.Close()
+
+		status, body := send(t, http.MethodPost, server.URL+"/adopt/any", "")
+		assert.Equal(t, http.StatusConflict, status)
+		assert.Contains(t, string(body), ErrShelterEmpty.Error())
+
+		send(t, http.MethodPost, server.URL+"/animals", `{"species":"dog","name":"Dog1"}`)
+		status, _ = send(t, http.MethodPost, server.URL+"/adopt/cat", "")
+		assert.Equal(t, http.StatusConflict, status)
+	})
+
+	t.Run("POST /adopt returns 404 for unknown species", func(t *testing.T) {
+		server := newServer()
+		defer server.Close()
+
+		status, body := send(t, http.MethodPost, server.URL+"/adopt/rabbit", "")
+		assert.Equal(t, http.StatusNotFound, status)
+		assert.Contains(t, string(body), ErrUnknownSpecies.Error())
+	})
+
+	t.Run("Unknown routes and methods are rejected", func(t *testing.T) {
+		server := newServer()
+		defer server.Close()
+
+		status, _ := send(t, http.MethodGet, server.URL+"/unknown", "")
+		assert.Equal(t, http.StatusNotFound, status)
+
+		status, _ = send(t, http.MethodPost, server.URL+"/adopt/cat/extra", "")
+		assert.Equal(t, http.StatusNotFound, status)
+
+		status, _ = send(t, http.MethodGet, server.URL+"/adopt/cat", "")
+		assert.Equal(t, http.StatusMethodNotAllowed, status)
+
+		status, _ = send(t, http.MethodDelete, server.URL+"/animals", "")
+		assert.Equal(t, http.StatusMethodNotAllowed, status)
+	})
+}
+
+func newCatAndDogShelter() *Shelter {
+	shelter := newShelter()
+	_ = shelter.RegisterSpecies("cat", &Cat{})
+	_ = shelter.RegisterSpecies("dog", &Dog{})
+	return shelter
+}
+
+const (
+	stressWorkers         = 8
+	stressValuesPerWorker = 2000
+)
+
+func TestConcurrentStacks(t *testing.T) {
+	stacks := map[string]func() StackInterface[int]{
+		"SyncStack":      func() StackInterface[int] { return newSyncStack[int](newStack[int]()) },
+		"SyncSliceStack": func() StackInterface[int] { return newSyncStack[int](newSliceStack[int]()) },
+		"TreiberStack":   func() StackInterface[int] { return newTreiberStack[int]() },
+	}
+
+	for name, createStack := range stacks {
+		createStack := createStack
+
+		t.Run(name+" keeps LIFO order given single goroutine", func(t *testing.T) {
+			stack := createStack()
+			_, ok := stack.Peek()
+			assert.False(t, ok)
+			stack.Push(44)
+			stack.Push(12)
+			value, _ := stack.Peek()
+			assert.Equal(t, 12, value)
+			value, _ = stack.TryPop()
+			assert.Equal(t, 12, value)
+			value, _ = stack.TryPop()
+			assert.Equal(t, 44, value)
+			_, ok = stack.TryPop()
+			assert.False(t, ok)
+			assert.True(t, stack.IsEmpty())
+		})
+
+		t.Run(name+" pops every pushed value once given concurrent access", func(t *testing.T) {
+			stack := createStack()
+			popped := runStress(stack.Push, stack.TryPop)
+
+			assertEveryValuePoppedOnce(t, popped)
+			assert.True(t, stack.IsEmpty())
+		})
+	}
+}
+
+func TestConcurrentQueues(t *testing.T) {
+	queues := map[string]func() QueueInterface[int]{
+		"SyncQueue":         func() QueueInterface[int] { return newSyncQueue[int](newQueue[int]()) },
+		"SyncRingQueue":     func() QueueInterface[int] { return newSyncQueue[int](newRingQueue[int]()) },
+		"MichaelScottQueue": func() QueueInterface[int] { return newMichaelScottQueue[int]() },
+	}
+
+	for name, createQueue := range queues {
+		createQueue := createQueue
+
+		t.Run(name+" keeps FIFO order given single goroutine", func(t *testing.T) {
+			queue := createQueue()
+			_, ok := queue.Peek()
+			assert.False(t, ok)
+			queue.Enqueue(44)
+			queue.Enqueue(12)
+			value, _ := queue.Peek()
+			assert.Equal(t, 44, value)
+			value, _ = queue.TryDequeue()
+			assert.Equal(t, 44, value)
+			value, _ = queue.TryDequeue()
+			assert.Equal(t, 12, value)
+			_, ok = queue.TryDequeue()
+			assert.False(t, ok)
+			assert.True(t, queue.IsEmpty())
+		})
+
+		t.Run(name+" dequeues every value once in producer order given concurrent access", func(t *testing.T) {
+			queue := createQueue()
+			dequeued := runStress(queue.Enqueue, queue.TryDequeue)
+
+			assertEveryValuePoppedOnce(t, dequeued)
+			for _, values := range dequeued {
+				lastSeen := make(map[int]int)
+				for _, value := range values {
+					producer := value / stressValuesPerWorker
+					if previous, seen := lastSeen[producer]; seen {
+						assert.Less(t, previous, value)
+					}
+					lastSeen[producer] = value
+				}
+			}
+			assert.True(t, queue.IsEmpty())
+		})
+	}
+}
+
+func TestSyncMinStack(t *testing.T) {
+	stack := newSyncMinStack[int]()
+	popped := runStress(stack.Push, stack.TryPop)
+
+	assertEveryValuePoppedOnce(t, popped)
+	_, ok := stack.TryMin()
+	assert.False(t, ok)
+
+	stack.Push(15)
+	stack.Push(12)
+	minimum, _ := stack.TryMin()
+	assert.Equal(t, 12, minimum)
+	top, _ := stack.Peek()
+	assert.Equal(t, 12, top)
+}
+
+func TestSyncShelter(t *testing.T) {
+	shelter := newSyncShelter()
+	_ = shelter.RegisterSpecies("cat", &Cat{})
+	_ = shelter.RegisterSpecies("dog", &Dog{})
+	var waitGroup sync.WaitGroup
+
+	for worker := 0; worker < stressWorkers; worker++ {
+		waitGroup.Add(1)
+		go func(worker int) {
+			defer waitGroup.Done()
+			for i := 0; i < 100; i++ {
+				if i%2 == 0 {
+					_ = shelter.Enqueue(newCat(fmt.Sprintf("Cat%d-%d", worker, i)))
+				} else {
+					_ = shelter.Enqueue(newDog(fmt.Sprintf("Dog%d-%d", worker, i)))
+				}
+			}
+		}(worker)
+	}
+	waitGroup.Wait()
+
+	adopted := make(chan string, stressWorkers*100)
+	for worker := 0; worker < stressWorkers; worker++ {
+		waitGroup.Add(1)
+		go func(worker int) {
+			defer waitGroup.Done()
+			species := "cat"
+			if worker%2 == 1 {
+				species = "dog"
+			}
+			for i := 0; i < 50; i++ {
+				animal, _ := shelter.DequeueSpecies(species)
+				adopted <- animal.GetName()
+			}
+		}(worker)
+	}
+	waitGroup.Wait()
+	close(adopted)
+
+	names := make(map[string]bool)
+	for name := range adopted {
+		names[name] = true
+	}
+	assert.Equal(t, stressWorkers*50, len(names))
+	assert.Equal(t, stressWorkers*50, countAnimals(shelter))
+}
+
+func countAnimals(shelter *SyncShelter) int {
+	count := 0
+	for _, err := shelter.DequeueAny(); err == nil; _, err = shelter.DequeueAny() {
+		count++
+	}
+	return count
+}
+
+func runStress(push func(int), pop func() (int, bool)) [][]int {
+	total := int64(stressWorkers * stressValuesPerWorker)
+	var poppedCount int64
+	var waitGroup sync.WaitGroup
+
+	for worker := 0; worker < stressWorkers; worker++ {
+		waitGroup.Add(1)
+		go func(worker int) {
+			defer waitGroup.Done()
+			for i := 0; i < stressValuesPerWorker; i++ {
+				push(worker*stressValuesPerWorker + i)
+			}
+		}(worker)
+	}
+
+	popped := make([][]int, stressWorkers)
+	for worker := 0; worker < stressWorkers; worker++ {
+		waitGroup.Add(1)
+		go func(worker int) {
+			defer waitGroup.Done()
+			for atomic.LoadInt64(&poppedCount) < total {
+				if value, ok := pop(); ok {
+					popped[worker] = append(popped[worker], value)
+					atomic.AddInt64(&poppedCount, 1)
+				} else {
+					runtime.Gosched()
+				}
+			}
+		}(worker)
+	}
+
+	waitGroup.Wait()
+	return popped
+}
+
+func assertEveryValuePoppedOnce(t *testing.T, popped [][]int) {
+	seen := make(map[int]bool)
+	for _, values := range popped {
+		for _, value := range values {
+			assert.False(t, seen[value], "value %d popped twice", value)
+			seen[value] = true
+		}
+	}
+	assert.Equal(t, stressWorkers*stressValuesPerWorker, len(seen))
 }